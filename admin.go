@@ -0,0 +1,264 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GlobalName is the name used by the admin handler for the Global Log. The Global
+// Log is always listed, unless another Log is registered with this name.
+const GlobalName = "global"
+
+var registry = struct {
+	e map[string]*entry
+	g entry
+	sync.Mutex
+}{e: make(map[string]*entry)}
+
+type entry struct {
+	l Log
+	t *time.Timer
+	d time.Time
+	s []saved
+	n uint64
+}
+type saved struct {
+	x    Log
+	l, k Level
+}
+type status struct {
+	Name       string     `json:"name"`
+	Level      string     `json:"level,omitempty"`
+	PrintLevel string     `json:"print_level,omitempty"`
+	Revert     *time.Time `json:"revert,omitempty"`
+}
+
+// Register adds the Log 'l' with the name 'n' to the list of Logs that can be
+// viewed and changed by the admin handler. Any Log previously registered with
+// the same name is replaced. If 'l' is nil, this is the same as calling
+// 'Unregister'.
+func Register(n string, l Log) {
+	if l == nil {
+		Unregister(n)
+		return
+	}
+	registry.Lock()
+	if e, ok := registry.e[n]; ok && e.t != nil {
+		e.t.Stop()
+	}
+	registry.e[n] = &entry{l: l}
+	registry.Unlock()
+}
+
+// Unregister removes the Log registered with the name 'n', if it exists. Any
+// pending level revert for the Log is canceled.
+func Unregister(n string) {
+	registry.Lock()
+	if e, ok := registry.e[n]; ok {
+		if e.t != nil {
+			e.t.Stop()
+		}
+		delete(registry.e, n)
+	}
+	registry.Unlock()
+}
+
+// AdminHandler returns a 'http.Handler' that can be used to view and change the
+// Level and print Level of the Global Log and any Logs added with 'Register'.
+//
+// A GET request returns a JSON array of the Logs, containing the "name", "level",
+// "print_level" and "revert" (if a revert is pending) values of each Log.
+//
+// A PUT or POST request changes the Levels of a Log, using the "name" (defaults
+// to the Global Log), "level" and "print_level" query or form values. Levels use
+// the names returned by 'Level.String' (or any name accepted by 'ParseLevel').
+// The optional "ttl" value (such as "10m") will revert the Levels to the values
+// they had before the change once the duration has passed. The changed Log is
+// returned as a JSON object.
+func AdminHandler() http.Handler {
+	return http.HandlerFunc(admin)
+}
+func levelName(l Level) string {
+	if l >= invalidLevel {
+		return ""
+	}
+	return strings.TrimSpace(l.String())
+}
+func (e *entry) log() Log {
+	if e.l == nil {
+		return Global
+	}
+	return e.l
+}
+func lookup(n string) *entry {
+	if e, ok := registry.e[n]; ok {
+		return e
+	}
+	if n == GlobalName {
+		return &registry.g
+	}
+	return nil
+}
+func (e *entry) revert(n uint64) {
+	registry.Lock()
+	// NOTE(dij): The counter check prevents a timer that fired while a new
+	//            change was being made from reverting the new change.
+	if e.n == n && e.t != nil {
+		for _, v := range e.s {
+			if v.x.SetLevel(v.l); v.k < invalidLevel {
+				v.x.SetPrintLevel(v.k)
+			}
+		}
+		e.t, e.d, e.s = nil, time.Time{}, nil
+	}
+	registry.Unlock()
+}
+func (e *entry) known() bool {
+	for i := range e.s {
+		if e.s[i].l >= invalidLevel {
+			return false
+		}
+	}
+	return true
+}
+func save(x Log, s []saved) []saved {
+	// NOTE(dij): The Levels of each Log in a Multi are saved separately, as
+	//            they may differ and 'Multi.SetLevel' sets them all at once.
+	switch m := x.(type) {
+	case Multi:
+		for i := range m {
+			s = save(m[i], s)
+		}
+		return s
+	case *Multi:
+		if m != nil {
+			return save(*m, s)
+		}
+	}
+	l, k := levelsOf(x)
+	return append(s, saved{x: x, l: l, k: k})
+}
+func (e *entry) status(n string) status {
+	l, k := levelsOf(e.log())
+	s := status{Name: n, Level: levelName(l), PrintLevel: levelName(k)}
+	if e.t != nil {
+		t := e.d
+		s.Revert = &t
+	}
+	return s
+}
+func admin(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		registry.Lock()
+		s := make([]status, 0, len(registry.e)+1)
+		if _, ok := registry.e[GlobalName]; !ok {
+			s = append(s, registry.g.status(GlobalName))
+		}
+		for k, v := range registry.e {
+			s = append(s, v.status(k))
+		}
+		registry.Unlock()
+		sort.Slice(s, func(i, j int) bool { return s[i].Name < s[j].Name })
+		reply(w, http.StatusOK, s)
+		return
+	case http.MethodPut, http.MethodPost:
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var (
+		n       = r.FormValue("name")
+		l, k    = invalidLevel, invalidLevel
+		d       time.Duration
+		err     error
+		v, p, t = r.FormValue("level"), r.FormValue("print_level"), r.FormValue("ttl")
+	)
+	if len(n) == 0 {
+		n = GlobalName
+	}
+	if len(v) == 0 && len(p) == 0 {
+		http.Error(w, `a "level" or "print_level" value is required`, http.StatusBadRequest)
+		return
+	}
+	if len(v) > 0 {
+		if l, err = ParseLevel(v); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if len(p) > 0 {
+		if k, err = ParseLevel(p); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if len(t) > 0 {
+		if d, err = time.ParseDuration(t); err != nil || d <= 0 {
+			http.Error(w, `invalid ttl "`+t+`"`, http.StatusBadRequest)
+			return
+		}
+	}
+	registry.Lock()
+	e := lookup(n)
+	if e == nil {
+		registry.Unlock()
+		http.Error(w, `unknown log "`+n+`"`, http.StatusNotFound)
+		return
+	}
+	x := e.log()
+	if x == nil {
+		registry.Unlock()
+		http.Error(w, `log "`+n+`" is not set`, http.StatusConflict)
+		return
+	}
+	if e.t == nil {
+		if e.s = save(x, e.s[:0]); d > 0 && !e.known() {
+			e.s = nil
+			registry.Unlock()
+			http.Error(w, `cannot revert log "`+n+`": current level is unknown`, http.StatusBadRequest)
+			return
+		}
+	} else {
+		e.t.Stop()
+		e.t, e.d = nil, time.Time{}
+	}
+	if e.n++; l < invalidLevel {
+		x.SetLevel(l)
+	}
+	if k < invalidLevel {
+		x.SetPrintLevel(k)
+	}
+	if d > 0 {
+		c := e.n
+		e.d, e.t = time.Now().Add(d), time.AfterFunc(d, func() { e.revert(c) })
+	}
+	s := e.status(n)
+	registry.Unlock()
+	reply(w, http.StatusOK, s)
+}
+func reply(w http.ResponseWriter, c int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(c)
+	json.NewEncoder(w).Encode(v)
+}
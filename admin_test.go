@@ -0,0 +1,68 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAdminMultiRevert(t *testing.T) {
+	var (
+		a = Writer(ioutil.Discard, Error)
+		b = Writer(ioutil.Discard, Info)
+	)
+	Register("test-multi", Multiple(a, b))
+	defer Unregister("test-multi")
+	w := httptest.NewRecorder()
+	AdminHandler().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/?name=test-multi&level=debug&ttl=50ms", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("admin returned status %d: %s", w.Code, w.Body.String())
+	}
+	if l, _ := levelsOf(a); l != Debug {
+		t.Fatalf("Log Level is %s, expected Debug", l)
+	}
+	for i := 0; i < 100; i++ {
+		if l, _ := levelsOf(a); l != Debug {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if l, _ := levelsOf(a); l != Error {
+		t.Fatalf("Log Level reverted to %s, expected Error", l)
+	}
+	if l, _ := levelsOf(b); l != Info {
+		t.Fatalf("Log Level reverted to %s, expected Info", l)
+	}
+}
+func TestAdminNilGlobal(t *testing.T) {
+	g := Global
+	Global = nil
+	defer func() { Global = g }()
+	w := httptest.NewRecorder()
+	AdminHandler().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/?level=debug", nil))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("admin returned status %d, expected %d", w.Code, http.StatusConflict)
+	}
+	w = httptest.NewRecorder()
+	AdminHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("admin returned status %d, expected %d", w.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// Block is an Overflow policy that instructs an asynchronous Log to wait
+	// for space in the queue when it is full. No entries are dropped, but logging
+	// calls may block until the background writer catches up.
+	Block Overflow = iota
+	// DropNewest is an Overflow policy that instructs an asynchronous Log to
+	// discard the entry being written when the queue is full.
+	DropNewest
+	// DropOldest is an Overflow policy that instructs an asynchronous Log to
+	// discard the oldest queued entry to make room for the entry being written
+	// when the queue is full.
+	DropOldest
+)
+
+// Overflow is an alias of a byte that represents the action an asynchronous
+// Log takes when its queue is full.
+type Overflow uint8
+type queue struct {
+	d uint64
+	w io.Writer
+	c chan *[]byte
+	x chan struct{}
+	g sync.WaitGroup
+	p Overflow
+}
+type settingAsync struct {
+	n int
+	p Overflow
+}
+
+// Async will create an Option interface that instructs the Log to write entries
+// asynchronously. Entries are formatted when logged and then placed in a queue
+// of size 'n' that is written by a background goroutine. The Overflow policy
+// 'p' determines what happens when the queue is full.
+//
+// Asynchronous Logs should be closed (or synced) before the program exits to
+// ensure all queued entries are written. Values of 'n' less than or equal to zero
+// disable asynchronous writing.
+func Async(n int, p Overflow) Option {
+	return settingAsync{n: n, p: p}
+}
+func (settingAsync) setting() setting {
+	return setAsync
+}
+func (q *queue) run() {
+	for b := range q.c {
+		q.w.Write(*b)
+		put(b)
+		q.g.Done()
+	}
+	close(q.x)
+}
+func (q *queue) close() {
+	if q.c == nil {
+		return
+	}
+	close(q.c)
+	<-q.x
+	q.c = nil
+}
+func newQueue(w io.Writer, s settingAsync) *queue {
+	q := &queue{w: w, p: s.p, c: make(chan *[]byte, s.n), x: make(chan struct{})}
+	go q.run()
+	return q
+}
+func (q *queue) push(b *[]byte) error {
+	if q.c == nil {
+		put(b)
+		return os.ErrClosed
+	}
+	q.g.Add(1)
+	switch q.p {
+	case DropNewest:
+		select {
+		case q.c <- b:
+		default:
+			put(b)
+			q.g.Done()
+			atomic.AddUint64(&q.d, 1)
+		}
+	case DropOldest:
+		for {
+			select {
+			case q.c <- b:
+				return nil
+			default:
+			}
+			select {
+			case o := <-q.c:
+				put(o)
+				q.g.Done()
+				atomic.AddUint64(&q.d, 1)
+			default:
+			}
+		}
+	default:
+		q.c <- b
+	}
+	return nil
+}
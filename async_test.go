@@ -0,0 +1,136 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+type gateWriter struct {
+	s chan struct{}
+	g chan struct{}
+	b []string
+	m sync.Mutex
+	o sync.Once
+}
+
+func newGate() *gateWriter {
+	return &gateWriter{s: make(chan struct{}), g: make(chan struct{})}
+}
+func (w *gateWriter) lines() string {
+	w.m.Lock()
+	s := strings.Join(w.b, ",")
+	w.m.Unlock()
+	return s
+}
+func (w *gateWriter) Write(b []byte) (int, error) {
+	w.o.Do(func() { close(w.s) })
+	<-w.g
+	w.m.Lock()
+	w.b = append(w.b, strings.TrimPrefix(strings.TrimSpace(string(b)), "[ WARN]: "))
+	w.m.Unlock()
+	return len(b), nil
+}
+func testAsync(t *testing.T, p Overflow) (*gateWriter, Log) {
+	w := newGate()
+	l := Writer(w, Flags(0), Async(2, p))
+	// NOTE(dij): Wait for the background writer to be blocked on the first
+	//            entry, so the next two entries fill the queue.
+	l.Warning("1")
+	select {
+	case <-w.s:
+	case <-time.After(time.Second * 5):
+		t.Fatal("background writer did not start")
+	}
+	l.Warning("2")
+	l.Warning("3")
+	return w, l
+}
+func TestAsyncAlign(t *testing.T) {
+	if o := unsafe.Offsetof(queue{}.d); o%8 != 0 {
+		t.Fatalf("queue.d is at offset %d, which is not 8-byte aligned", o)
+	}
+}
+func TestAsyncDropNewest(t *testing.T) {
+	w, l := testAsync(t, DropNewest)
+	l.Warning("4")
+	l.Warning("5")
+	if d := l.(dropper).Dropped(); d != 2 {
+		t.Fatalf("Log dropped %d entries, expected 2", d)
+	}
+	close(w.g)
+	l.(syncer).Sync()
+	if s := w.lines(); s != "1,2,3" {
+		t.Fatalf("unexpected output %q", s)
+	}
+}
+func TestAsyncDropOldest(t *testing.T) {
+	w, l := testAsync(t, DropOldest)
+	l.Warning("4")
+	l.Warning("5")
+	if d := l.(dropper).Dropped(); d != 2 {
+		t.Fatalf("Log dropped %d entries, expected 2", d)
+	}
+	close(w.g)
+	l.(syncer).Sync()
+	if s := w.lines(); s != "1,4,5" {
+		t.Fatalf("unexpected output %q", s)
+	}
+}
+func TestAsyncBlock(t *testing.T) {
+	w, l := testAsync(t, Block)
+	x := make(chan struct{})
+	go func() {
+		l.Warning("4")
+		close(x)
+	}()
+	select {
+	case <-x:
+		t.Fatal("write did not block on a full queue")
+	case <-time.After(time.Millisecond * 50):
+	}
+	close(w.g)
+	<-x
+	l.(syncer).Sync()
+	if s := w.lines(); s != "1,2,3,4" {
+		t.Fatalf("unexpected output %q", s)
+	}
+	if d := l.(dropper).Dropped(); d != 0 {
+		t.Fatalf("Log dropped %d entries, expected zero", d)
+	}
+}
+func TestAsyncClose(t *testing.T) {
+	w := newGate()
+	close(w.g)
+	l := Writer(w, Flags(0), Async(64, Block))
+	for _, s := range []string{"1", "2", "3", "4", "5"} {
+		l.Warning(s)
+	}
+	if err := l.(io.Closer).Close(); err != nil {
+		t.Fatal(err)
+	}
+	if s := w.lines(); s != "1,2,3,4,5" {
+		t.Fatalf("unexpected output %q", s)
+	}
+	if l.Warning("6"); w.lines() != "1,2,3,4,5" {
+		t.Fatal("entry written after Close was written")
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"io"
+	"os"
+)
+
+const (
+	// NoColor is a logging setting that instructs a writer backed Log to never
+	// color its output, even if the writer is a terminal.
+	//
+	// This setting has no effect on non-writer backed logging instances.
+	NoColor = settingColor(1 << iota)
+	// ForceColor is a logging setting that instructs a writer backed Log to always
+	// color its output, even if the writer is not a terminal.
+	//
+	// This setting has no effect on non-writer backed logging instances.
+	ForceColor
+	// ColorLine is a logging setting that instructs a writer backed Log to color
+	// the entire line of each entry instead of only the "[LEVEL]" tag, when the
+	// output is colored.
+	//
+	// This setting has no effect on non-writer backed logging instances.
+	ColorLine
+)
+
+const colorReset = "\x1b[0m"
+
+type settingColor uint8
+type colorText settingColor
+
+func (settingColor) setting() setting {
+	return setColor
+}
+func (l Level) color() string {
+	switch l {
+	case Trace:
+		return "\x1b[90m"
+	case Debug:
+		return "\x1b[36m"
+	case Info:
+		return "\x1b[32m"
+	case Warning:
+		return "\x1b[33m"
+	case Error:
+		return "\x1b[31m"
+	case Fatal:
+		return "\x1b[1;31m"
+	case Panic:
+		return "\x1b[1;35m"
+	}
+	return ""
+}
+func colored(w io.Writer, c settingColor) bool {
+	switch {
+	case c&NoColor != 0:
+		return false
+	case c&ForceColor != 0:
+		return true
+	}
+	f, ok := w.(*os.File)
+	if !ok || f == nil {
+		return false
+	}
+	i, err := f.Stat()
+	if err != nil || i.Mode().IsRegular() {
+		return false
+	}
+	if d, err := os.Stat(os.DevNull); err == nil && os.SameFile(i, d) {
+		return false
+	}
+	// NOTE(dij): Follow the https://no-color.org and FORCE_COLOR conventions,
+	//            which take priority over terminal detection.
+	if len(os.Getenv("NO_COLOR")) > 0 {
+		return false
+	}
+	if v := os.Getenv("FORCE_COLOR"); len(v) > 0 && v != "0" && v != "false" {
+		return true
+	}
+	return i.Mode()&os.ModeCharDevice != 0 && os.Getenv("TERM") != "dumb"
+}
+func (c colorText) Format(b []byte, f uint8, r Record) []byte {
+	return appendText(b, f, &r, settingColor(c)|ForceColor)
+}
@@ -0,0 +1,151 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Config is a struct that describes a tree of Log instances. A Config can be
+// loaded from JSON using the 'Load' function, or created directly and built using
+// the 'Build' function.
+//
+// The 'Type' value selects the Log that is created and must be one of "console",
+// "stdout", "stderr", "file" or "multi". The "file" type requires the 'Path'
+// value and the "multi" type uses the 'Sinks' values to create a Multi that
+// contains each child Log, which may also be of the "multi" type.
+//
+// The 'Level', 'PrintLevel', 'Flags', 'Prefix' and 'Format' values use the same
+// names as the 'FromEnv' function. Any of these values that are set on a "multi"
+// Config are used as the defaults for its Sinks.
+type Config struct {
+	Type       string   `json:"type"`
+	Path       string   `json:"path,omitempty"`
+	Level      string   `json:"level,omitempty"`
+	Flags      string   `json:"flags,omitempty"`
+	Prefix     string   `json:"prefix,omitempty"`
+	Format     string   `json:"format,omitempty"`
+	PrintLevel string   `json:"print_level,omitempty"`
+	Sinks      []Config `json:"sinks,omitempty"`
+	Append     bool     `json:"append,omitempty"`
+}
+
+// Load will read a JSON Config from the Reader 'r' and will return the Log tree
+// that it describes. An error is returned if the JSON is invalid, contains unknown
+// keys or if any of the Logs cannot be created.
+//
+// Calling this function again will create a new Log tree, which can be swapped
+// into a Holder (such as one used as the Global Log) to reload the configuration.
+func Load(r io.Reader) (Log, error) {
+	var (
+		c Config
+		d = json.NewDecoder(r)
+	)
+	d.DisallowUnknownFields()
+	if err := d.Decode(&c); err != nil {
+		return nil, errors.New("cannot parse config: " + err.Error())
+	}
+	return c.Build()
+}
+
+// Build will create the Log tree described by this Config. An error is returned
+// if any of the values are invalid or if any of the Logs cannot be created.
+//
+// If an error occurs, any Logs already created by this function are closed.
+func (c Config) Build() (Log, error) {
+	return c.build(nil, "config")
+}
+func (c Config) options(o []Option) ([]Option, error) {
+	r := make([]Option, len(o), len(o)+6)
+	copy(r, o)
+	if len(c.Level) > 0 {
+		l, err := ParseLevel(c.Level)
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, l)
+	}
+	if len(c.PrintLevel) > 0 {
+		l, err := ParseLevel(c.PrintLevel)
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, PrintLevel(l))
+	}
+	if len(c.Flags) > 0 {
+		f, err := parseFlags(c.Flags)
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, Flags(f))
+	}
+	if len(c.Format) > 0 {
+		e, err := parseEncoding(c.Format)
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, Format(e))
+	}
+	if len(c.Prefix) > 0 {
+		r = append(r, Prefix(c.Prefix))
+	}
+	return r, nil
+}
+func (c Config) build(o []Option, n string) (Log, error) {
+	o, err := c.options(o)
+	if err != nil {
+		return nil, errors.New(n + ": " + err.Error())
+	}
+	switch strings.ToLower(c.Type) {
+	case "console":
+		return Console(o...), nil
+	case "stdout":
+		return Writer(os.Stdout, o...), nil
+	case "stderr":
+		return Writer(os.Stderr, o...), nil
+	case "file":
+		if len(c.Path) == 0 {
+			return nil, errors.New(n + `: "file" type requires a path`)
+		}
+		if c.Append {
+			o = append(o, Append)
+		}
+		l, err := File(c.Path, o...)
+		if err != nil {
+			return nil, errors.New(n + ": " + err.Error())
+		}
+		return l, nil
+	case "multi":
+		m := make(Multi, 0, len(c.Sinks))
+		for i := range c.Sinks {
+			l, err := c.Sinks[i].build(o, n+".sinks["+strconv.Itoa(i)+"]")
+			if err != nil {
+				m.Close()
+				return nil, err
+			}
+			m = append(m, l)
+		}
+		return &m, nil
+	case "":
+		return nil, errors.New(n + ": missing type")
+	}
+	return nil, errors.New(n + `: invalid type "` + c.Type + `"`)
+}
@@ -0,0 +1,74 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadUnknownKey(t *testing.T) {
+	_, err := Load(strings.NewReader(`{"type": "stderr", "levle": "info"}`))
+	if err == nil || !strings.Contains(err.Error(), "levle") {
+		t.Fatalf("Load returned error %v, expected an unknown key error", err)
+	}
+}
+func TestLoadInherit(t *testing.T) {
+	l, err := Load(strings.NewReader(`{
+		"type": "multi", "level": "debug", "flags": "none",
+		"sinks": [
+			{"type": "stderr"},
+			{"type": "multi", "level": "error", "sinks": [{"type": "stdout", "print_level": "warn"}]}
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := *l.(*Multi)
+	if len(m) != 2 {
+		t.Fatalf("Multi contains %d Logs, expected 2", len(m))
+	}
+	if v, k := levelsOf(m[0]); v != Debug || k != Info {
+		t.Fatalf("first sink Levels are %s, %s, expected Debug, Info", v, k)
+	}
+	if v, k := levelsOf(m[1]); v != Error || k != Warning {
+		t.Fatalf("nested sink Levels are %s, %s, expected Error, Warning", v, k)
+	}
+	if f := m[0].(*stream).f; f != 0 {
+		t.Fatalf("first sink flags are %d, expected zero", f)
+	}
+	if f := (*m[1].(*Multi))[0].(*stream).f; f != 0 {
+		t.Fatalf("nested sink flags are %d, expected zero", f)
+	}
+}
+func TestBuildCloses(t *testing.T) {
+	p := filepath.Join(tempDir(t), "test.log")
+	_, err := Config{Type: "multi", Sinks: []Config{
+		{Type: "file", Path: p},
+		{Type: "file", Level: "loud", Path: p},
+	}}.Build()
+	if err == nil || !strings.HasPrefix(err.Error(), "config.sinks[1]: ") {
+		t.Fatalf("Build returned error %v, expected an error for the second sink", err)
+	}
+	files.Lock()
+	for _, f := range files.e {
+		if f.f.p == p {
+			t.Error("the file Log created before the error was not closed")
+		}
+	}
+	files.Unlock()
+}
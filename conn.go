@@ -0,0 +1,86 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"time"
+)
+
+const dialTimeout = time.Second * 10
+
+type remote struct {
+	stream
+	c io.Closer
+}
+type settingTLS struct {
+	*tls.Config
+}
+
+// TLS will create an Option interface that sets the TLS configuration used by
+// network backed Logs when connecting with the "tls" network.
+//
+// If not specified, the default configuration is used with the server name set
+// to the host of the address. This setting has no effect on non-network backed
+// logging instances.
+func TLS(c *tls.Config) Option {
+	return settingTLS{c}
+}
+func (settingTLS) setting() setting {
+	return setTLS
+}
+func isStream(n string) bool {
+	switch n {
+	case "udp", "udp4", "udp6", "unixgram", "ip", "ip4", "ip6":
+		return false
+	}
+	return true
+}
+func dial(n, a string, t *tls.Config) (net.Conn, error) {
+	if n != "tls" {
+		return net.DialTimeout(n, a, dialTimeout)
+	}
+	if t == nil {
+		t = new(tls.Config)
+	}
+	if len(t.ServerName) == 0 {
+		if h, _, err := net.SplitHostPort(a); err == nil {
+			t = t.Clone()
+			t.ServerName = h
+		}
+	}
+	x, err := tls.DialWithDialer(&net.Dialer{Timeout: dialTimeout}, "tcp", a, t)
+	if err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// Close will close the connection used by this Log. If this Log is asynchronous,
+// this will wait for all queued entries to be written first. Any Log functions
+// called after this will not write any output.
+func (r *remote) Close() error {
+	r.close()
+	return r.c.Close()
+}
+func (r *remote) With(v ...Field) Log {
+	if len(v) == 0 {
+		return r
+	}
+	return &remote{c: r.c, stream: *r.stream.With(v...).(*stream)}
+}
@@ -0,0 +1,210 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+)
+
+var keys struct {
+	e []contextKey
+	sync.RWMutex
+}
+
+type ctxLog struct{}
+type contextKey struct {
+	k interface{}
+	n string
+}
+
+// ContextKey registers the Context key 'k' with the Field name 'n'. When any of
+// the '*Context' logging functions are called, the value of each registered key
+// in the supplied Context (if not nil) is added to the entry as a Field.
+//
+// This can be used to automatically include request scoped values, such as a
+// request ID or tenant name, in all entries. Registering the same key again
+// will replace the Field name used.
+func ContextKey(n string, k interface{}) {
+	keys.Lock()
+	for i := range keys.e {
+		if keys.e[i].k == k {
+			keys.e[i].n = n
+			keys.Unlock()
+			return
+		}
+	}
+	keys.e = append(keys.e, contextKey{k: k, n: n})
+	keys.Unlock()
+}
+
+// FromContext returns the Log stored in the supplied Context by 'NewContext'. If
+// no Log is stored or the Context is nil, the Global logger is returned instead.
+//
+// If the Global logger is nil, this returns the NOP Log.
+func FromContext(x context.Context) Log {
+	if x != nil {
+		if l, ok := x.Value(ctxLog{}).(Log); ok && l != nil {
+			return l
+		}
+	}
+	if Global == nil {
+		return NOP
+	}
+	return Global
+}
+
+// ContextFields returns the Fields that would be added to an entry by any of the
+// '*Context' logging functions for the supplied Context. This includes the values
+// of any registered Context keys and the IDs returned by the TraceExtractor.
+func ContextFields(x context.Context) []Field {
+	if x == nil {
+		return nil
+	}
+	var f []Field
+	keys.RLock()
+	for i := range keys.e {
+		if v := x.Value(keys.e[i].k); v != nil {
+			f = append(f, Field{Key: keys.e[i].n, Value: v})
+		}
+	}
+	keys.RUnlock()
+	return traceFields(x, f)
+}
+
+// NewContext returns a copy of the parent Context that stores the supplied Log.
+// The Log can be retrieved using 'FromContext' and is used by all the '*Context'
+// logging functions.
+func NewContext(x context.Context, l Log) context.Context {
+	if x == nil {
+		x = context.Background()
+	}
+	return context.WithValue(x, ctxLog{}, l)
+}
+func logContext(x context.Context, l Level, m string, v []interface{}) Log {
+	g := FromContext(x)
+	if f := ContextFields(x); len(f) > 0 {
+		g = g.With(f...)
+	}
+	logAs(g, l, 2, m, v)
+	return g
+}
+
+// InfoContext writes an informational message to the Log stored in the Context
+// (or the Global logger), including any registered Context values as Fields.
+//
+// The function arguments are similar to 'fmt.Sprintf' and 'fmt.Printf'. The
+// first argument is a string that can contain formatting characters. The second
+// argument is a vardict of interfaces that can be omitted or used in the supplied
+// format string.
+func InfoContext(x context.Context, m string, v ...interface{}) {
+	logContext(x, Info, m, v)
+}
+
+// ErrorContext writes an error message to the Log stored in the Context (or the
+// Global logger), including any registered Context values as Fields.
+//
+// The function arguments are similar to 'fmt.Sprintf' and 'fmt.Printf'. The
+// first argument is a string that can contain formatting characters. The second
+// argument is a vardict of interfaces that can be omitted or used in the supplied
+// format string.
+func ErrorContext(x context.Context, m string, v ...interface{}) {
+	logContext(x, Error, m, v)
+}
+
+// FatalContext writes a fatal message to the Log stored in the Context (or the
+// Global logger), including any registered Context values as Fields.
+//
+// This function will result in the program exiting with a non-zero error code
+// after being called, unless the 'logx.FatalExits' setting is 'false'. The
+// function arguments are similar to 'fmt.Sprintf' and 'fmt.Printf'. The first
+// argument is a string that can contain formatting characters. The second argument
+// is a vardict of interfaces that can be omitted or used in the supplied format
+// string.
+func FatalContext(x context.Context, m string, v ...interface{}) {
+	g := logContext(x, Fatal, m, v)
+	if FatalExits {
+		if s, ok := g.(syncer); ok {
+			s.Sync()
+		}
+		os.Exit(1)
+	}
+}
+
+// TraceContext writes a tracing message to the Log stored in the Context (or the
+// Global logger), including any registered Context values as Fields.
+//
+// The function arguments are similar to 'fmt.Sprintf' and 'fmt.Printf'. The
+// first argument is a string that can contain formatting characters. The second
+// argument is a vardict of interfaces that can be omitted or used in the supplied
+// format string.
+func TraceContext(x context.Context, m string, v ...interface{}) {
+	logContext(x, Trace, m, v)
+}
+
+// DebugContext writes a debugging message to the Log stored in the Context (or
+// the Global logger), including any registered Context values as Fields.
+//
+// The function arguments are similar to 'fmt.Sprintf' and 'fmt.Printf'. The
+// first argument is a string that can contain formatting characters. The second
+// argument is a vardict of interfaces that can be omitted or used in the supplied
+// format string.
+func DebugContext(x context.Context, m string, v ...interface{}) {
+	logContext(x, Debug, m, v)
+}
+
+// PrintContext writes a message to the Log stored in the Context (or the Global
+// logger), including any registered Context values as Fields.
+//
+// The function arguments are similar to 'fmt.Sprintf' and 'fmt.Printf'. The
+// first argument is a string that can contain formatting characters. The second
+// argument is a vardict of interfaces that can be omitted or used in the supplied
+// format string.
+//
+// This function is affected by the setting of 'SetPrintLevel'. By default,
+// this will print as an 'Info' logging message.
+func PrintContext(x context.Context, m string, v ...interface{}) {
+	logContext(x, Print, m, v)
+}
+
+// PanicContext writes a panic message to the Log stored in the Context (or the
+// Global logger), including any registered Context values as Fields.
+//
+// This function will result in the program exiting with a Go 'panic()' after
+// being called. The function arguments are similar to 'fmt.Sprintf' and 'fmt.Printf'.
+// The first argument is a string that can contain formatting characters. The
+// second argument is a vardict of interfaces that can be omitted or used in
+// the supplied format string.
+func PanicContext(x context.Context, m string, v ...interface{}) {
+	g := logContext(x, Panic, m, v)
+	if s, ok := g.(syncer); ok {
+		s.Sync()
+	}
+	panic(fmt.Sprintf(m, v...))
+}
+
+// WarningContext writes a warning message to the Log stored in the Context (or
+// the Global logger), including any registered Context values as Fields.
+//
+// The function arguments are similar to 'fmt.Sprintf' and 'fmt.Printf'. The
+// first argument is a string that can contain formatting characters. The second
+// argument is a vardict of interfaces that can be omitted or used in the supplied
+// format string.
+func WarningContext(x context.Context, m string, v ...interface{}) {
+	logContext(x, Warning, m, v)
+}
@@ -0,0 +1,188 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+type dedup struct {
+	x Log
+	s *repeat
+}
+type repeat struct {
+	t *time.Timer
+	o *dedup
+	v string
+	m sync.Mutex
+	d time.Duration
+	n uint64
+	g uint64
+	l Level
+}
+
+// Dedup returns a Log that wraps the Log 'l' and collapses consecutive identical
+// messages. Only the first message is written and any identical messages (with
+// the same Level, message and Fields) that follow it are counted instead.
+//
+// Once a different message is written, or once 'd' has passed without another
+// identical message, a single "previous message repeated N times" message is
+// written with the Level of the repeated message. If 'd' is less than or equal
+// to zero, the summary is only written when a different message is written or
+// when 'Sync' or 'Close' are called.
+func Dedup(l Log, d time.Duration) Log {
+	return &dedup{x: l, s: &repeat{d: d, l: invalidLevel}}
+}
+func (d *dedup) SetLevel(l Level) {
+	d.x.SetLevel(l)
+}
+func (d *dedup) SetPrefix(p string) {
+	d.x.SetPrefix(p)
+}
+func (d *dedup) SetPrintLevel(n Level) {
+	d.x.SetPrintLevel(n)
+}
+func (d *dedup) levels() (Level, Level) {
+	return levelsOf(d.x)
+}
+func (d *dedup) With(f ...Field) Log {
+	if len(f) == 0 {
+		return d
+	}
+	return &dedup{x: d.x.With(f...), s: d.s}
+}
+
+// Sync will write the summary message if the last message was repeated, then
+// will call the 'Sync' function on the wrapped Log if it supports it.
+func (d *dedup) Sync() error {
+	d.s.m.Lock()
+	d.s.flush(1)
+	d.s.m.Unlock()
+	if x, ok := d.x.(syncer); ok {
+		return x.Sync()
+	}
+	return nil
+}
+
+// Close will write the summary message if the last message was repeated, then
+// will call the 'Close' function on the wrapped Log if it supports it.
+func (d *dedup) Close() error {
+	d.s.m.Lock()
+	d.s.flush(1)
+	d.s.m.Unlock()
+	if x, ok := d.x.(io.Closer); ok {
+		return x.Close()
+	}
+	return nil
+}
+func (r *repeat) flush(c int) {
+	if r.t != nil {
+		r.t.Stop()
+		r.t = nil
+	}
+	if r.g++; r.n > 0 {
+		logAs(r.o.x, r.l, c+1, "previous message repeated %d times", []interface{}{r.n})
+	}
+	r.o, r.v, r.l, r.n = nil, "", invalidLevel, 0
+}
+func (r *repeat) expire(g uint64) {
+	r.m.Lock()
+	// NOTE(dij): The generation check prevents a timer that fired while a new
+	//            message was being written from flushing the new message.
+	if r.g == g {
+		r.flush(1)
+	}
+	r.m.Unlock()
+}
+func (d *dedup) Log(l Level, c int, m string, v ...interface{}) {
+	// NOTE(dij): Messages the wrapped Log would drop are ignored, so they don't
+	//            break a run of identical messages.
+	if !enabled(d.x, l) {
+		return
+	}
+	s := message(m, v)
+	d.s.m.Lock()
+	if l != Fatal && l != Panic && d.s.o == d && d.s.l == l && d.s.v == s {
+		if d.s.n++; d.s.d > 0 {
+			if d.s.t == nil {
+				g := d.s.g
+				d.s.t = time.AfterFunc(d.s.d, func() { d.s.expire(g) })
+			} else {
+				d.s.t.Reset(d.s.d)
+			}
+		}
+		d.s.m.Unlock()
+		return
+	}
+	d.s.flush(c + 1)
+	if l != Fatal && l != Panic {
+		d.s.o, d.s.v, d.s.l = d, s, l
+	}
+	// NOTE(dij): Write while holding the lock, so the summary and the new
+	//            message can't be reordered by another goroutine.
+	logAs(d.x, l, c+1, "", []interface{}{s})
+	d.s.m.Unlock()
+}
+func (d *dedup) Print(v ...interface{}) {
+	d.Log(Print, 1, "", v...)
+}
+func (d *dedup) Panic(v ...interface{}) {
+	d.Log(Panic, 1, "", v...)
+	d.Sync()
+	panic(fmt.Sprint(v...))
+}
+func (d *dedup) Println(v ...interface{}) {
+	d.Log(Print, 1, "", v...)
+}
+func (d *dedup) Panicln(v ...interface{}) {
+	d.Log(Panic, 1, "", v...)
+	d.Sync()
+	panic(fmt.Sprint(v...))
+}
+func (d *dedup) Info(m string, v ...interface{}) {
+	d.Log(Info, 1, m, v...)
+}
+func (d *dedup) Error(m string, v ...interface{}) {
+	d.Log(Error, 1, m, v...)
+}
+func (d *dedup) Fatal(m string, v ...interface{}) {
+	d.Log(Fatal, 1, m, v...)
+	if FatalExits {
+		d.Sync()
+		os.Exit(1)
+	}
+}
+func (d *dedup) Trace(m string, v ...interface{}) {
+	d.Log(Trace, 1, m, v...)
+}
+func (d *dedup) Debug(m string, v ...interface{}) {
+	d.Log(Debug, 1, m, v...)
+}
+func (d *dedup) Printf(m string, v ...interface{}) {
+	d.Log(Print, 1, m, v...)
+}
+func (d *dedup) Panicf(m string, v ...interface{}) {
+	d.Log(Panic, 1, m, v...)
+	d.Sync()
+	panic(fmt.Sprintf(m, v...))
+}
+func (d *dedup) Warning(m string, v ...interface{}) {
+	d.Log(Warning, 1, m, v...)
+}
@@ -0,0 +1,34 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDedupFiltered(t *testing.T) {
+	var b bytes.Buffer
+	l := Dedup(Writer(&b, Warning, Flags(0)), 0)
+	l.Error("down")
+	l.Debug("hidden")
+	l.Error("down")
+	l.Error("down")
+	l.Warning("up")
+	if s := b.String(); s != "[ERROR]: down\n[ERROR]: previous message repeated 2 times\n[ WARN]: up\n" {
+		t.Fatalf("unexpected output %q", s)
+	}
+}
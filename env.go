@@ -0,0 +1,164 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// FromEnv will create a Log instance using the values of the environment variables
+// that start with the prefix 'p' followed by an underscore. If 'p' is empty,
+// "LOGX" is used. The following variables are read (shown with the "LOGX" prefix):
+//
+//	LOGX_LEVEL        The Log Level name or number, such as "info" or "warn".
+//	LOGX_PRINT_LEVEL  The Level used by the 'Print*' functions.
+//	LOGX_FLAGS        A comma separated list of flag names, such as "date,time,utc".
+//	                  Valid names are "date", "time", "micro", "longfile",
+//	                  "shortfile", "utc", "std" and "none". The numeric value
+//	                  of the flags (0 to 63) may also be used.
+//	LOGX_PREFIX       The Log prefix.
+//	LOGX_FORMAT       The output Encoding name, "text", "json" or "logfmt".
+//	LOGX_FILE         The path of a file to log to instead of the console.
+//	LOGX_APPEND       If true, the file will be appended to instead of truncated.
+//	LOGX_CONSOLE      If true and LOGX_FILE is set, log to the console and the
+//	                  file using a Multi.
+//
+// Any unset variables will use the default values of the 'Console' and 'File'
+// functions. An error is returned if any of the variables contain invalid values
+// or if the file cannot be opened.
+func FromEnv(p string) (Log, error) {
+	if len(p) == 0 {
+		p = "LOGX"
+	}
+	p = strings.TrimSuffix(p, "_") + "_"
+	var o []Option
+	if s, ok := env(p + "LEVEL"); ok {
+		l, err := ParseLevel(s)
+		if err != nil {
+			return nil, errors.New(p + "LEVEL: " + err.Error())
+		}
+		o = append(o, l)
+	}
+	if s, ok := env(p + "PRINT_LEVEL"); ok {
+		l, err := ParseLevel(s)
+		if err != nil {
+			return nil, errors.New(p + "PRINT_LEVEL: " + err.Error())
+		}
+		o = append(o, PrintLevel(l))
+	}
+	if s, ok := env(p + "FLAGS"); ok {
+		f, err := parseFlags(s)
+		if err != nil {
+			return nil, errors.New(p + "FLAGS: " + err.Error())
+		}
+		o = append(o, Flags(f))
+	}
+	if s, ok := env(p + "FORMAT"); ok {
+		e, err := parseEncoding(s)
+		if err != nil {
+			return nil, errors.New(p + "FORMAT: " + err.Error())
+		}
+		o = append(o, Format(e))
+	}
+	if s, ok := os.LookupEnv(p + "PREFIX"); ok {
+		o = append(o, Prefix(s))
+	}
+	a, err := envBool(p + "APPEND")
+	if err != nil {
+		return nil, err
+	}
+	c, err := envBool(p + "CONSOLE")
+	if err != nil {
+		return nil, err
+	}
+	f, ok := env(p + "FILE")
+	if !ok {
+		return Console(o...), nil
+	}
+	if a {
+		o = append(o, Append)
+	}
+	l, err := File(f, o...)
+	if err != nil {
+		return nil, err
+	}
+	if !c {
+		return l, nil
+	}
+	return Multiple(Console(o...), l), nil
+}
+func env(k string) (string, bool) {
+	s := strings.TrimSpace(os.Getenv(k))
+	return s, len(s) > 0
+}
+func envBool(k string) (bool, error) {
+	s, ok := env(k)
+	if !ok {
+		return false, nil
+	}
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, errors.New(k + `: invalid boolean "` + s + `"`)
+	}
+	return v, nil
+}
+func parseFlags(s string) (int, error) {
+	if n, err := strconv.ParseUint(strings.TrimSpace(s), 10, 64); err == nil {
+		// NOTE(dij): Reject undefined flags, as the values above 63 include
+		//            unknown bits and 255 is the unset value of 'Flags'.
+		if m := FlagDate | FlagTime | FlagMicroseconds | FlagFileLong | FlagFileShort | FlagTimeUTC; n > uint64(m) {
+			return 0, errors.New(`invalid flags value "` + s + `" (must be between 0 and ` + strconv.Itoa(int(m)) + `)`)
+		}
+		return int(n), nil
+	}
+	var f uint8
+	for _, v := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '|' || r == ' ' }) {
+		switch strings.ToLower(v) {
+		case "date":
+			f |= FlagDate
+		case "time":
+			f |= FlagTime
+		case "micro", "microseconds":
+			f |= FlagMicroseconds
+		case "longfile":
+			f |= FlagFileLong
+		case "shortfile":
+			f |= FlagFileShort
+		case "utc":
+			f |= FlagTimeUTC
+		case "std", "standard":
+			f |= FlagStandard
+		case "none":
+		default:
+			return 0, errors.New(`invalid flag "` + v + `"`)
+		}
+	}
+	return int(f), nil
+}
+func parseEncoding(s string) (Encoding, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "text":
+		return Text, nil
+	case "json":
+		return JSON, nil
+	case "logfmt":
+		return Logfmt, nil
+	}
+	return Text, errors.New(`invalid format "` + s + `"`)
+}
@@ -0,0 +1,95 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func setEnv(t *testing.T, v map[string]string) {
+	for k, s := range v {
+		o, ok := os.LookupEnv(k)
+		os.Setenv(k, s)
+		k := k
+		t.Cleanup(func() {
+			if ok {
+				os.Setenv(k, o)
+			} else {
+				os.Unsetenv(k)
+			}
+		})
+	}
+}
+func TestFromEnv(t *testing.T) {
+	setEnv(t, map[string]string{
+		"TEST_LEVEL": "debug", "TEST_PRINT_LEVEL": "error", "TEST_FLAGS": "date, utc|shortfile", "TEST_FORMAT": "JSON",
+	})
+	l, err := FromEnv("TEST")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, k := levelsOf(l); v != Debug || k != Error {
+		t.Fatalf("Levels are %s, %s, expected Debug, Error", v, k)
+	}
+	if f := l.(*stream).f; f != FlagDate|FlagTimeUTC|FlagFileShort {
+		t.Fatalf("flags are %d, expected %d", f, FlagDate|FlagTimeUTC|FlagFileShort)
+	}
+	setEnv(t, map[string]string{"TEST_FLAGS": "63"})
+	if l, err = FromEnv("TEST_"); err != nil {
+		t.Fatal(err)
+	}
+	if f := l.(*stream).f; f != 63 {
+		t.Fatalf("flags are %d, expected 63", f)
+	}
+}
+func TestFromEnvFile(t *testing.T) {
+	setEnv(t, map[string]string{"TEST_FILE": filepath.Join(tempDir(t), "test.log"), "TEST_CONSOLE": "true"})
+	l, err := FromEnv("TEST")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.(io.Closer).Close()
+	if m, ok := l.(*Multi); !ok || len(*m) != 2 {
+		t.Fatalf("FromEnv returned %T, expected a Multi with two Logs", l)
+	}
+}
+func TestFromEnvErrors(t *testing.T) {
+	for _, v := range []struct {
+		k, v, e string
+	}{
+		{"TEST_LEVEL", "loud", "TEST_LEVEL: "},
+		{"TEST_PRINT_LEVEL", "12", "TEST_PRINT_LEVEL: "},
+		{"TEST_FLAGS", "64", "TEST_FLAGS: invalid flags value"},
+		{"TEST_FLAGS", "128", "TEST_FLAGS: invalid flags value"},
+		{"TEST_FLAGS", "255", "TEST_FLAGS: invalid flags value"},
+		{"TEST_FLAGS", "date,seconds", `TEST_FLAGS: invalid flag "seconds"`},
+		{"TEST_FORMAT", "xml", `TEST_FORMAT: invalid format "xml"`},
+		{"TEST_APPEND", "maybe", `TEST_APPEND: invalid boolean "maybe"`},
+		{"TEST_CONSOLE", "2", `TEST_CONSOLE: invalid boolean "2"`},
+		{"TEST_FILE", filepath.Join("missing", "dir", "test.log"), "cannot open"},
+	} {
+		t.Run(v.k+"="+v.v, func(t *testing.T) {
+			setEnv(t, map[string]string{v.k: v.v})
+			if _, err := FromEnv("TEST"); err == nil || !strings.Contains(err.Error(), v.e) {
+				t.Fatalf("FromEnv returned error %v, expected %q", err, v.e)
+			}
+		})
+	}
+}
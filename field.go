@@ -0,0 +1,48 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+// Field is a structured key/value pair that can be attached to a Log using the
+// 'With' function. Every message written by the resulting Log will include the
+// Field values.
+type Field struct {
+	Value interface{}
+	Key   string
+}
+
+// F is a shorthand function that returns a Field with the specified key and
+// value.
+func F(k string, v interface{}) Field {
+	return Field{Key: k, Value: v}
+}
+func quote(s string) bool {
+	if len(s) == 0 {
+		return true
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] <= ' ' || s[i] == '=' || s[i] == '"' || s[i] >= 0x7F {
+			return true
+		}
+	}
+	return false
+}
+func appendFields(b []byte, f []Field) []byte {
+	for i := range f {
+		b = append(appendKey(append(b, ' '), f[i].Key), '=')
+		b = appendLogfmtValue(b, f[i].Value)
+	}
+	return b
+}
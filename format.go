@@ -0,0 +1,396 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// Text is the default output Encoding. Each Record is written as a single
+	// line using the same layout as the standard 'log' package, followed by the
+	// Level tag, any Fields and the message.
+	Text Encoding = iota
+	// JSON is an output Encoding that writes each Record as a single line JSON
+	// object. The object contains the "time", "level", "caller", "prefix" and
+	// "msg" keys, followed by any Fields.
+	//
+	// The "time" and "caller" keys are only included when the related flags are
+	// set on the Log. The "time" value is formatted using RFC3339 and will include
+	// microseconds if 'FlagMicroseconds' is set.
+	JSON
+	// Logfmt is an output Encoding that writes each Record as a single line of
+	// space separated key=value pairs. Each line contains the "ts", "level",
+	// "caller", "prefix" and "msg" keys, followed by any Fields.
+	//
+	// The "ts" and "caller" keys follow the same rules as the 'JSON' Encoding.
+	// Values that contain spaces, quotes, '=' or control characters are quoted
+	// and escaped.
+	Logfmt
+)
+
+const (
+	timeJSON      = "2006-01-02T15:04:05Z07:00"
+	timeJSONMicro = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+const hex = "0123456789abcdef"
+
+// Record is a struct that contains the values of a single logging entry. Records
+// are created by a Log and are passed to a Formatter to be converted into their
+// output form.
+//
+// The 'File' and 'Line' values are only set when the Log has the 'FlagFileLong'
+// or 'FlagFileShort' flags set. The 'File' value will already be shortened if
+// 'FlagFileShort' is set. The 'Time' value will already be in UTC if the
+// 'FlagTimeUTC' flag is set.
+//
+// The 'Level' value may be out of the range of valid levels if this Record was
+// created by an 'Output' call, which does not have a Level.
+type Record struct {
+	Time    time.Time
+	Fields  []Field
+	File    string
+	Prefix  string
+	Message string
+	Line    int
+	Level   Level
+}
+
+// Encoding is an alias of a byte that represents one of the inbuilt Formatter
+// layouts. The 'Text' Encoding is the default layout used by all Logs.
+type Encoding uint8
+
+// Formatter is an interface that is used to convert a Record into the bytes
+// that are written to the output of a Log. Formatters can be set on a Log using
+// the 'Format' Option.
+//
+// The 'Format' function takes an output buffer, the flags set on the Log and the
+// Record to write. The function must append the Record output to the buffer and
+// return the resulting slice. Each Record output should end in a newline.
+//
+// Formatters must not retain the buffer or the Record after returning.
+type Formatter interface {
+	Format([]byte, uint8, Record) []byte
+}
+
+// Format will create an Option interface that will set the Formatter used by the
+// logging instance when created. Any Encoding constant may be used here.
+//
+// If this Option is not specified or is nil, the 'Text' Encoding is used.
+func Format(f Formatter) Option {
+	return settingFormat{f}
+}
+
+// Format fulfills the Formatter interface and writes the Record using the layout
+// of this Encoding. Invalid Encoding values will be treated as 'Text'.
+func (e Encoding) Format(b []byte, f uint8, r Record) []byte {
+	switch e {
+	case JSON:
+		return appendJSON(b, f, &r)
+	case Logfmt:
+		return appendLogfmt(b, f, &r)
+	}
+	return appendText(b, f, &r, 0)
+}
+func (l Level) name() string {
+	switch l {
+	case Trace:
+		return "trace"
+	case Debug:
+		return "debug"
+	case Info:
+		return "info"
+	case Warning:
+		return "warn"
+	case Error:
+		return "error"
+	case Fatal:
+		return "fatal"
+	case Panic:
+		return "panic"
+	}
+	return "invalid"
+}
+func appendJSONValue(b []byte, v interface{}) []byte {
+	switch x := v.(type) {
+	case nil:
+		return append(b, "null"...)
+	case string:
+		return appendJSONString(b, x)
+	case bool:
+		return strconv.AppendBool(b, x)
+	case int:
+		return strconv.AppendInt(b, int64(x), 10)
+	case int8:
+		return strconv.AppendInt(b, int64(x), 10)
+	case int16:
+		return strconv.AppendInt(b, int64(x), 10)
+	case int32:
+		return strconv.AppendInt(b, int64(x), 10)
+	case int64:
+		return strconv.AppendInt(b, x, 10)
+	case uint:
+		return strconv.AppendUint(b, uint64(x), 10)
+	case uint8:
+		return strconv.AppendUint(b, uint64(x), 10)
+	case uint16:
+		return strconv.AppendUint(b, uint64(x), 10)
+	case uint32:
+		return strconv.AppendUint(b, uint64(x), 10)
+	case uint64:
+		return strconv.AppendUint(b, x, 10)
+	case json.Marshaler:
+	case error:
+		return appendJSONString(b, x.Error())
+	case fmt.Stringer:
+		return appendJSONString(b, x.String())
+	}
+	o, err := json.Marshal(v)
+	if err != nil {
+		return appendJSONString(b, fmt.Sprint(v))
+	}
+	return append(b, o...)
+}
+func appendJSONString(b []byte, s string) []byte {
+	return append(appendJSONEscape(append(b, '"'), s), '"')
+}
+func appendJSONEscape(b []byte, s string) []byte {
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			switch {
+			case c == '"' || c == '\\':
+				b = append(b, '\\', c)
+			case c == '\n':
+				b = append(b, '\\', 'n')
+			case c == '\r':
+				b = append(b, '\\', 'r')
+			case c == '\t':
+				b = append(b, '\\', 't')
+			case c < ' ' || c == 0x7F:
+				b = append(b, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+			default:
+				b = append(b, c)
+			}
+			i++
+			continue
+		}
+		r, n := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && n == 1 {
+			b = append(b, "\ufffd"...)
+		} else {
+			b = append(b, s[i:i+n]...)
+		}
+		i += n
+	}
+	return b
+}
+func appendJSON(o []byte, f uint8, r *Record) []byte {
+	o = append(o, '{')
+	if f&(FlagDate|FlagTime|FlagMicroseconds) != 0 {
+		o = append(o, `"time":"`...)
+		if f&FlagMicroseconds != 0 {
+			o = r.Time.AppendFormat(o, timeJSONMicro)
+		} else {
+			o = r.Time.AppendFormat(o, timeJSON)
+		}
+		o = append(o, '"', ',')
+	}
+	if r.Level < invalidLevel {
+		o = append(o, `"level":"`...)
+		o = append(o, r.Level.name()...)
+		o = append(o, '"', ',')
+	}
+	if f&(FlagFileLong|FlagFileShort) != 0 {
+		o = append(appendJSONEscape(append(o, `"caller":"`...), r.File), ':')
+		o = append(strconv.AppendInt(o, int64(r.Line), 10), '"', ',')
+	}
+	if len(r.Prefix) > 0 {
+		o = appendJSONString(append(o, `"prefix":`...), r.Prefix)
+		o = append(o, ',')
+	}
+	s := r.Message
+	if len(s) > 0 && s[len(s)-1] == '\n' {
+		s = s[:len(s)-1]
+	}
+	o = appendJSONString(append(o, `"msg":`...), s)
+	for i := range r.Fields {
+		o = append(appendJSONString(append(o, ','), r.Fields[i].Key), ':')
+		o = appendJSONValue(o, r.Fields[i].Value)
+	}
+	return append(o, '}', '\n')
+}
+func appendKey(b []byte, k string) []byte {
+	if len(k) == 0 {
+		return append(b, '_')
+	}
+	for i := 0; i < len(k); {
+		if c := k[i]; c < utf8.RuneSelf {
+			if c <= ' ' || c == '=' || c == '"' || c == 0x7F {
+				b = append(b, '_')
+			} else {
+				b = append(b, c)
+			}
+			i++
+			continue
+		}
+		if r, n := utf8.DecodeRuneInString(k[i:]); r == utf8.RuneError && n == 1 {
+			b = append(b, '_')
+			i++
+		} else {
+			b = append(b, k[i:i+n]...)
+			i += n
+		}
+	}
+	return b
+}
+func appendLogfmtValue(b []byte, v interface{}) []byte {
+	switch x := v.(type) {
+	case nil:
+		return append(b, "null"...)
+	case string:
+		return appendLogfmtString(b, x)
+	case bool:
+		return strconv.AppendBool(b, x)
+	case int:
+		return strconv.AppendInt(b, int64(x), 10)
+	case int64:
+		return strconv.AppendInt(b, x, 10)
+	case uint:
+		return strconv.AppendUint(b, uint64(x), 10)
+	case uint64:
+		return strconv.AppendUint(b, x, 10)
+	case error:
+		return appendLogfmtString(b, x.Error())
+	case fmt.Stringer:
+		return appendLogfmtString(b, x.String())
+	}
+	return appendLogfmtString(b, fmt.Sprint(v))
+}
+func appendLogfmtString(b []byte, s string) []byte {
+	if quote(s) {
+		return appendJSONString(b, s)
+	}
+	return append(b, s...)
+}
+func appendLogfmt(o []byte, f uint8, r *Record) []byte {
+	if f&(FlagDate|FlagTime|FlagMicroseconds) != 0 {
+		if o = append(o, "ts="...); f&FlagMicroseconds != 0 {
+			o = r.Time.AppendFormat(o, timeJSONMicro)
+		} else {
+			o = r.Time.AppendFormat(o, timeJSON)
+		}
+		o = append(o, ' ')
+	}
+	if r.Level < invalidLevel {
+		o = append(o, "level="...)
+		o = append(o, r.Level.name()...)
+		o = append(o, ' ')
+	}
+	if f&(FlagFileLong|FlagFileShort) != 0 {
+		if o = append(o, "caller="...); quote(r.File) {
+			o = append(appendJSONEscape(append(o, '"'), r.File), ':')
+			o = append(strconv.AppendInt(o, int64(r.Line), 10), '"', ' ')
+		} else {
+			o = append(append(o, r.File...), ':')
+			o = append(strconv.AppendInt(o, int64(r.Line), 10), ' ')
+		}
+	}
+	if len(r.Prefix) > 0 {
+		o = appendLogfmtString(append(o, "prefix="...), r.Prefix)
+		o = append(o, ' ')
+	}
+	s := r.Message
+	if len(s) > 0 && s[len(s)-1] == '\n' {
+		s = s[:len(s)-1]
+	}
+	o = appendLogfmtString(append(o, "msg="...), s)
+	for i := range r.Fields {
+		o = append(appendKey(append(o, ' '), r.Fields[i].Key), '=')
+		o = appendLogfmtValue(o, r.Fields[i].Value)
+	}
+	return append(o, '\n')
+}
+func appendText(o []byte, f uint8, r *Record, c settingColor) []byte {
+	var (
+		b [28]byte
+		n int
+		v string
+	)
+	if c&ForceColor != 0 && r.Level < invalidLevel {
+		if v = r.Level.color(); c&ColorLine != 0 {
+			o = append(o, v...)
+		}
+	}
+	if f&FlagDate != 0 {
+		y, m, d := r.Time.Date()
+		n = itoa(&b, n, y, 4)
+		b[n] = '/'
+		n = itoa(&b, n+1, int(m), 2)
+		b[n] = '/'
+		n = itoa(&b, n+1, d, 2)
+		b[n] = ' '
+		n++
+	}
+	if f&(FlagTime|FlagMicroseconds) != 0 {
+		h, m, c := r.Time.Clock()
+		n = itoa(&b, n, h, 2)
+		b[n] = ':'
+		n = itoa(&b, n+1, m, 2)
+		b[n] = ':'
+		n = itoa(&b, n+1, c, 2)
+		if f&FlagMicroseconds != 0 {
+			b[n] = '.'
+			n = itoa(&b, n+1, r.Time.Nanosecond()/1e3, 6)
+		}
+		b[n] = ' '
+		n++
+	}
+	if o = append(o, b[:n]...); f&(FlagFileLong|FlagFileShort) != 0 {
+		o = append(o, r.File...)
+		o = append(o, ':')
+		n = itoa(&b, 0, r.Line, -1)
+		b[n] = ' '
+		o = append(o, b[:n+1]...)
+	}
+	if len(r.Prefix) > 0 {
+		o = append(o, r.Prefix...)
+		o = append(o, ' ')
+	}
+	if r.Level < invalidLevel {
+		if len(v) > 0 && c&ColorLine == 0 {
+			o = append(append(append(o, v...), '['), r.Level.String()...)
+			o = append(append(o, ']'), colorReset...)
+		} else {
+			o = append(append(append(o, '['), r.Level.String()...), ']')
+		}
+		o = append(appendFields(o, r.Fields), ':', ' ')
+	}
+	if o = append(o, r.Message...); len(v) > 0 && c&ColorLine != 0 {
+		if len(r.Message) > 0 && o[len(o)-1] == '\n' {
+			o = o[:len(o)-1]
+		}
+		return append(append(o, colorReset...), '\n')
+	}
+	if len(r.Message) == 0 || o[len(o)-1] != '\n' {
+		o = append(o, '\n')
+	}
+	return o
+}
@@ -0,0 +1,78 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func testRecord() Record {
+	return Record{
+		Time:    time.Date(2023, 1, 2, 15, 4, 5, 123456789, time.UTC),
+		File:    "main.go",
+		Line:    42,
+		Prefix:  "my app",
+		Message: "say \"hi\"\\\n\tdone\x01\x7f\xffé\n",
+		Level:   Warning,
+		Fields: []Field{
+			{Key: "a=b c", Value: "x y"},
+			{Key: "", Value: ""},
+			{Key: "q\"k", Value: `v"`},
+			{Key: "n", Value: 5},
+			{Key: "bad\xff", Value: "\xff"},
+			{Key: "ok", Value: "plain"},
+		},
+	}
+}
+func TestFormatGolden(t *testing.T) {
+	const f = FlagTimeUTC | FlagMicroseconds | FlagFileShort
+	for _, v := range []struct {
+		n string
+		e Encoding
+		f uint8
+		o string
+	}{
+		{"JSON", JSON, 0, `{"level":"warn","prefix":"my app","msg":"say \"hi\"\\\n\tdone\u0001\u007f�é","a=b c":"x y","":"","q\"k":"v\"","n":5,"bad�":"�","ok":"plain"}` + "\n"},
+		{"JSONFlags", JSON, f, `{"time":"2023-01-02T15:04:05.123456Z","level":"warn","caller":"main.go:42","prefix":"my app","msg":"say \"hi\"\\\n\tdone\u0001\u007f�é","a=b c":"x y","":"","q\"k":"v\"","n":5,"bad�":"�","ok":"plain"}` + "\n"},
+		{"Logfmt", Logfmt, 0, `level=warn prefix="my app" msg="say \"hi\"\\\n\tdone\u0001\u007f�é" a_b_c="x y" _="" q_k="v\"" n=5 bad_="�" ok=plain` + "\n"},
+		{"LogfmtFlags", Logfmt, f, `ts=2023-01-02T15:04:05.123456Z level=warn caller=main.go:42 prefix="my app" msg="say \"hi\"\\\n\tdone\u0001\u007f�é" a_b_c="x y" _="" q_k="v\"" n=5 bad_="�" ok=plain` + "\n"},
+		{"Text", Text, 0, `my app [ WARN] a_b_c="x y" _="" q_k="v\"" n=5 bad_="�" ok=plain: say "hi"\` + "\n\tdone\x01\x7f\xffé\n"},
+		{"TextFlags", Text, f, `15:04:05.123456 main.go:42 my app [ WARN] a_b_c="x y" _="" q_k="v\"" n=5 bad_="�" ok=plain: say "hi"\` + "\n\tdone\x01\x7f\xffé\n"},
+	} {
+		t.Run(v.n, func(t *testing.T) {
+			if o := string(v.e.Format(nil, v.f, testRecord())); o != v.o {
+				t.Fatalf("unexpected output:\n%q\nexpected:\n%q", o, v.o)
+			}
+		})
+	}
+}
+func TestFormatLogfmtEmpty(t *testing.T) {
+	r := Record{Level: invalidLevel, Fields: []Field{{Key: "k", Value: ""}, {Key: "v", Value: nil}}}
+	if o := string(Logfmt.Format(nil, 0, r)); o != `msg="" k="" v=null`+"\n" {
+		t.Fatalf("unexpected output %q", o)
+	}
+}
+func TestFormatJSONValid(t *testing.T) {
+	var v map[string]interface{}
+	if err := json.Unmarshal(JSON.Format(nil, FlagStandard|FlagFileShort, testRecord()), &v); err != nil {
+		t.Fatalf("JSON output is not valid: %s", err)
+	}
+	if v["msg"] != "say \"hi\"\\\n\tdone\x01\x7f�é" {
+		t.Fatalf("unexpected msg value %q", v["msg"])
+	}
+}
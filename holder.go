@@ -0,0 +1,266 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// Holder is a Log that writes to a Log that can be replaced atomically while it
+// is being used. This can be used as the Global Log to allow for a configuration
+// to be reloaded:
+//
+//	h := logx.NewHolder(l)
+//	logx.Global = h
+//	// Later, after loading a new Log 'n'.
+//	if c, ok := h.Swap(n).(io.Closer); ok {
+//	    c.Close()
+//	}
+//
+// Use the 'NewHolder' function to create a Holder.
+type Holder struct {
+	v atomic.Value
+	m sync.Mutex
+}
+type held struct {
+	Log
+}
+
+// NewHolder returns a Holder that writes to the Log 'l'. If 'l' is nil, the NOP
+// Log is used instead.
+func NewHolder(l Log) *Holder {
+	h := new(Holder)
+	h.Swap(l)
+	return h
+}
+
+// Load returns the Log that this Holder is currently writing to. The empty value
+// of a Holder will return the NOP Log.
+func (h *Holder) Load() Log {
+	if v, ok := h.v.Load().(held); ok {
+		return v.Log
+	}
+	return NOP
+}
+
+// Swap atomically replaces the Log that this Holder is writing to with 'l' and
+// returns the previous Log. If 'l' is nil, the NOP Log is used instead.
+//
+// The previous Log is not closed and may still be used by calls that started
+// before the swap.
+func (h *Holder) Swap(l Log) Log {
+	if l == nil {
+		l = NOP
+	}
+	// NOTE(dij): The lock makes the Load and Store a single operation, so each
+	//            previous Log is returned by exactly one Swap. 'Load' does not
+	//            need the lock.
+	h.m.Lock()
+	o, _ := h.v.Load().(held)
+	h.v.Store(held{l})
+	h.m.Unlock()
+	return o.Log
+}
+
+// SetLevel changes the current logging level of the current Log.
+func (h *Holder) SetLevel(l Level) {
+	h.Load().SetLevel(l)
+}
+
+// SetPrefix changes the current logging prefix of the current Log.
+func (h *Holder) SetPrefix(p string) {
+	h.Load().SetPrefix(p)
+}
+
+// SetPrintLevel sets the logging level used when 'Print*' statements are called
+// on the current Log.
+func (h *Holder) SetPrintLevel(n Level) {
+	h.Load().SetPrintLevel(n)
+}
+func (h *Holder) levels() (Level, Level) {
+	return levelsOf(h.Load())
+}
+
+// With returns a child Log of the current Log that includes the supplied Fields.
+//
+// The returned Log is not affected by any later calls to 'Swap'.
+func (h *Holder) With(f ...Field) Log {
+	return h.Load().With(f...)
+}
+
+// Sync will call the 'Sync' function on the current Log if it supports it.
+func (h *Holder) Sync() error {
+	if x, ok := h.Load().(syncer); ok {
+		return x.Sync()
+	}
+	return nil
+}
+
+// Close will call the 'Close' function on the current Log if it supports it.
+func (h *Holder) Close() error {
+	if x, ok := h.Load().(io.Closer); ok {
+		return x.Close()
+	}
+	return nil
+}
+
+// Log fulfills the LogWriter interface and writes the message to the current
+// Log using the Level 'l'.
+func (h *Holder) Log(l Level, c int, m string, v ...interface{}) {
+	logAs(h.Load(), l, c+1, m, v)
+}
+
+// Print writes a message to the logger.
+//
+// The function arguments are similar to 'fmt.Sprint' and 'fmt.Print'. The only
+// argument is a vardict of interfaces that can be used to output a string value.
+//
+// This function is affected by the setting of 'SetPrintLevel'. By default,
+// this will print as an 'Info' logging message.
+func (h *Holder) Print(v ...interface{}) {
+	h.Log(Print, 1, "", v...)
+}
+
+// Panic writes a panic message to the logger.
+//
+// This function will result in the program exiting with a Go 'panic()' after
+// being called. The function arguments are similar to 'fmt.Sprint' and 'fmt.Print.'
+// The only argument is a vardict of interfaces that can be used to output a
+// string value.
+func (h *Holder) Panic(v ...interface{}) {
+	h.Log(Panic, 1, "", v...)
+	h.Sync()
+	panic(fmt.Sprint(v...))
+}
+
+// Println writes a message to the logger.
+//
+// The function arguments are similar to fmt.Sprintln and fmt.Println. The only
+// argument is a vardict of interfaces that can be used to output a string value.
+//
+// This function is affected by the setting of 'SetPrintLevel'. By default,
+// this will print as an 'Info' logging message.
+func (h *Holder) Println(v ...interface{}) {
+	h.Log(Print, 1, "", v...)
+}
+
+// Panicln writes a panic message to the logger.
+//
+// This function will result in the program exiting with a Go 'panic()' after
+// being called. The function arguments are similar to 'fmt.Sprintln' and
+// 'fmt.Println'. The only argument is a vardict of interfaces that
+// can be used to output a string value.
+func (h *Holder) Panicln(v ...interface{}) {
+	h.Log(Panic, 1, "", v...)
+	h.Sync()
+	panic(fmt.Sprint(v...))
+}
+
+// Info writes an informational message to the logger.
+//
+// The function arguments are similar to 'fmt.Sprintf' and 'fmt.Printf'. The
+// first argument is a string that can contain formatting characters. The second
+// argument is a vardict of interfaces that can be omitted or used in the supplied
+// format string.
+func (h *Holder) Info(m string, v ...interface{}) {
+	h.Log(Info, 1, m, v...)
+}
+
+// Error writes an error message to the logger.
+//
+// The function arguments are similar to 'fmt.Sprintf' and 'fmt.Printf'. The
+// first argument is a string that can contain formatting characters. The second
+// argument is a vardict of interfaces that can be omitted or used in the supplied
+// format string.
+func (h *Holder) Error(m string, v ...interface{}) {
+	h.Log(Error, 1, m, v...)
+}
+
+// Fatal writes a fatal message to the logger.
+//
+// This function will result in the program exiting with a non-zero error code
+// after being called, unless the 'logx.FatalExits' setting is 'false'. The
+// function arguments are similar to 'fmt.Sprintf' and 'fmt.Printf'. The first
+// argument is a string that can contain formatting characters. The second argument
+// is a vardict of interfaces that can be omitted or used in the supplied format
+// string.
+func (h *Holder) Fatal(m string, v ...interface{}) {
+	h.Log(Fatal, 1, m, v...)
+	if FatalExits {
+		h.Sync()
+		os.Exit(1)
+	}
+}
+
+// Trace writes a tracing message to the logger.
+//
+// The function arguments are similar to 'fmt.Sprintf' and 'fmt.Printf'. The
+// first argument is a string that can contain formatting characters. The second
+// argument is a vardict of interfaces that can be omitted or used in the supplied
+// format string.
+func (h *Holder) Trace(m string, v ...interface{}) {
+	h.Log(Trace, 1, m, v...)
+}
+
+// Debug writes a debugging message to the logger.
+//
+// The function arguments are similar to 'fmt.Sprintf' and 'fmt.Printf'. The
+// first argument is a string that can contain formatting characters. The second
+// argument is a vardict of interfaces that can be omitted or used in the supplied
+// format string.
+func (h *Holder) Debug(m string, v ...interface{}) {
+	h.Log(Debug, 1, m, v...)
+}
+
+// Printf writes a message to the logger.
+//
+// The function arguments are similar to 'fmt.Sprintf' and 'fmt.Printf'. The
+// first argument is a string that can contain formatting characters. The second
+// argument is a vardict of interfaces that can be omitted or used in the supplied
+// format string.
+//
+// This function is affected by the setting of 'SetPrintLevel'. By default,
+// this will print as an 'Info' logging message.
+func (h *Holder) Printf(m string, v ...interface{}) {
+	h.Log(Print, 1, m, v...)
+}
+
+// Panicf writes a panic message to the logger.
+//
+// This function will result in the program exiting with a Go 'panic()' after
+// being called. The function arguments are similar to 'fmt.Sprintf' and 'fmt.Printf'.
+// The first argument is a string that can contain formatting characters. The
+// second argument is a vardict of interfaces that can be omitted or used in
+// the supplied format string.
+func (h *Holder) Panicf(m string, v ...interface{}) {
+	h.Log(Panic, 1, m, v...)
+	h.Sync()
+	panic(fmt.Sprintf(m, v...))
+}
+
+// Warning writes a warning message to the logger.
+//
+// The function arguments are similar to 'fmt.Sprintf' and 'fmt.Printf'. The
+// first argument is a string that can contain formatting characters. The second
+// argument is a vardict of interfaces that can be omitted or used in the supplied
+// format string.
+func (h *Holder) Warning(m string, v ...interface{}) {
+	h.Log(Warning, 1, m, v...)
+}
@@ -0,0 +1,69 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"io/ioutil"
+	"sync"
+	"testing"
+)
+
+func TestHolderSwap(t *testing.T) {
+	var (
+		f = Writer(ioutil.Discard)
+		h = NewHolder(f)
+		s = make(map[Log]int)
+		g sync.WaitGroup
+		m sync.Mutex
+		v = make([]Log, 64)
+	)
+	for i := range v {
+		v[i] = Writer(ioutil.Discard)
+	}
+	for i := range v {
+		g.Add(1)
+		go func(l Log) {
+			o := h.Swap(l)
+			m.Lock()
+			s[o]++
+			m.Unlock()
+			g.Done()
+		}(v[i])
+	}
+	g.Wait()
+	s[h.Load()]++
+	if len(s) != len(v)+1 {
+		t.Fatalf("%d different Logs were returned, expected %d", len(s), len(v)+1)
+	}
+	for _, n := range s {
+		if n != 1 {
+			t.Fatal("a Log was returned by more than one Swap")
+		}
+	}
+	if s[f] != 1 {
+		t.Fatal("the first Log was not returned")
+	}
+}
+func TestHolderEmpty(t *testing.T) {
+	var h Holder
+	if h.Load() != NOP {
+		t.Fatal("empty Holder did not return the NOP Log")
+	}
+	h.Info("ignored")
+	if o := h.Swap(NOP); o != nil {
+		t.Fatalf("empty Holder Swap returned %v, expected nil", o)
+	}
+}
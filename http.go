@@ -0,0 +1,422 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// NDJSON is a Payload that instructs a HTTP backed Log to send each batch
+	// as newline delimited records. This is the default Payload.
+	NDJSON Payload = iota
+	// JSONArray is a Payload that instructs a HTTP backed Log to send each batch
+	// as a JSON array of records. This requires that the Log uses the 'JSON'
+	// Encoding (the default for HTTP backed Logs).
+	JSONArray
+)
+
+const (
+	httpRetries  = 3
+	httpTimeout  = time.Second * 10
+	httpInterval = time.Second * 5
+	httpMaxCount = 100
+	httpMaxSize  = 1 << 20
+)
+
+// Payload is an alias of a byte that represents the body layout used by a HTTP
+// backed Log. Payload values can be used as an Option for HTTP backed Logs.
+type Payload uint8
+type batch struct {
+	b []byte
+	n int
+}
+type poster struct {
+	d uint64
+	h *http.Client
+	r http.Header
+	k chan struct{}
+	f chan chan error
+	x chan struct{}
+	e chan struct{}
+	u string
+	q []batch
+	c batch
+	m sync.Mutex
+	i time.Duration
+	b time.Duration
+	w time.Duration
+	n int
+	s int
+	l int
+	t int
+	p Payload
+	z bool
+	o bool
+}
+type endpoint struct {
+	stream
+	p *poster
+}
+type settingBatch struct {
+	n, s int
+	d    time.Duration
+}
+type settingHeader struct {
+	k, v string
+}
+
+// HTTP will create a HTTP backed Log instance that sends batches of entries to
+// the specified URL using POST requests. Entries use the 'JSON' Encoding unless
+// the 'Format' option is specified.
+//
+// A batch is sent once it holds the maximum count of entries, reaches the maximum
+// size in bytes, or once the flush interval has passed. These can be changed
+// with the 'Batch' option. Batches are sent by a background goroutine and failed
+// requests that return a 5xx or 429 status (or fail to connect) are retried with
+// the delays set by the 'Backoff' option. Batches that cannot be sent are counted
+// by the 'Dropped' function.
+//
+// The 'Header', 'Compress', 'TLS', 'WriteTimeout' (the request timeout) and
+// 'Backlog' (the maximum number of entries waiting to be sent) options, along
+// with a Payload value, can be used to change the requests made.
+//
+// The returned Log supports the 'Close', 'Sync' and 'Dropped' functions. An
+// error is only returned if the URL is invalid.
+func HTTP(u string, o ...Option) (Log, error) {
+	v, err := url.Parse(u)
+	if err != nil {
+		return nil, err
+	}
+	if v.Scheme != "http" && v.Scheme != "https" {
+		return nil, errors.New(`invalid URL scheme "` + v.Scheme + `"`)
+	}
+	var (
+		t settingTLS
+		h = httpTimeout
+		p = &poster{
+			u: u, r: make(http.Header), n: httpMaxCount, s: httpMaxSize, i: httpInterval, l: defaultBacklog,
+			b: defaultBackoff, w: maxBackoff, k: make(chan struct{}, 1), f: make(chan chan error),
+			x: make(chan struct{}), e: make(chan struct{}),
+		}
+	)
+	for i := range o {
+		if o[i] == nil {
+			continue
+		}
+		switch o[i].setting() {
+		case setTLS:
+			t, _ = o[i].(settingTLS)
+		case setBatch:
+			b, _ := o[i].(settingBatch)
+			if b.n > 0 {
+				p.n = b.n
+			}
+			if b.s > 0 {
+				p.s = b.s
+			}
+			if b.d > 0 {
+				p.i = b.d
+			}
+		case setHeader:
+			x, _ := o[i].(settingHeader)
+			p.r.Add(x.k, x.v)
+		case setPayload:
+			p.p, _ = o[i].(Payload)
+		case setCompress:
+			z, _ := o[i].(settingCompress)
+			p.z = bool(z)
+		case setBackoff:
+			if x, _ := o[i].(settingBackoff); x.b > 0 {
+				if p.b, p.w = x.b, x.w; p.w < p.b {
+					p.w = p.b
+				}
+			}
+		case setTimeout:
+			x, _ := o[i].(settingTimeout)
+			h = time.Duration(x)
+		case setBacklog:
+			x, _ := o[i].(settingBacklog)
+			p.l = int(x)
+		}
+	}
+	p.h = &http.Client{Timeout: h}
+	if t.Config != nil {
+		x := http.DefaultTransport.(*http.Transport).Clone()
+		x.TLSClientConfig = t.Config
+		p.h.Transport = x
+	}
+	go p.run()
+	return &endpoint{p: p, stream: *Writer(p, append([]Option{Format(JSON)}, o...)...).(*stream)}, nil
+}
+
+// Batch will create an Option interface that sets the limits used by a HTTP backed
+// Log to batch entries. A batch is sent once it holds 'n' entries, is at least
+// 's' bytes in size, or once 'd' has passed since the last batch was sent. Any
+// values less than or equal to zero keep the default value.
+//
+// The defaults are 100 entries, 1MB and five seconds. This setting has no effect
+// on non-HTTP backed logging instances.
+func Batch(n, s int, d time.Duration) Option {
+	return settingBatch{n: n, s: s, d: d}
+}
+
+// Header will create an Option interface that adds the header 'k' with the value
+// 'v' to each request made by a HTTP backed Log. This Option may be specified
+// multiple times.
+//
+// This setting has no effect on non-HTTP backed logging instances.
+func Header(k, v string) Option {
+	return settingHeader{k: k, v: v}
+}
+func (p *poster) run() {
+	t := time.NewTicker(p.i)
+	defer t.Stop()
+	for {
+		select {
+		case <-p.k:
+		case <-t.C:
+			p.m.Lock()
+			p.queue()
+			p.m.Unlock()
+		case r := <-p.f:
+			p.m.Lock()
+			p.queue()
+			p.m.Unlock()
+			r <- p.sendAll()
+			continue
+		case <-p.x:
+			p.m.Lock()
+			p.queue()
+			p.m.Unlock()
+			p.sendAll()
+			close(p.e)
+			return
+		}
+		p.sendAll()
+	}
+}
+func (p *poster) queue() {
+	if p.c.n == 0 {
+		return
+	}
+	p.q, p.t = append(p.q, p.c), p.t+p.c.n
+	p.c = batch{}
+	for p.l > 0 && p.t > p.l && len(p.q) > 1 {
+		atomic.AddUint64(&p.d, uint64(p.q[0].n))
+		p.t -= p.q[0].n
+		p.q[0] = batch{}
+		p.q = p.q[1:]
+	}
+}
+
+// Sync will send any entries waiting to be sent and will block until they are
+// sent (or fail). The returned error is the last error returned by a request.
+func (e *endpoint) Sync() error {
+	e.flush()
+	r := make(chan error, 1)
+	select {
+	case e.p.f <- r:
+		return <-r
+	case <-e.p.e:
+		return os.ErrClosed
+	}
+}
+
+// Close will send any entries waiting to be sent and will stop the background
+// goroutine. If this Log is asynchronous, this will wait for all queued entries
+// to be written first. Any Log functions called after this will not write any
+// output.
+func (e *endpoint) Close() error {
+	e.close()
+	return e.p.Close()
+}
+func (p *poster) Close() error {
+	p.m.Lock()
+	if p.o {
+		p.m.Unlock()
+		return nil
+	}
+	p.o = true
+	p.m.Unlock()
+	close(p.x)
+	<-p.e
+	return nil
+}
+
+// Dropped returns the number of entries that were discarded because they could
+// not be sent or because too many entries were waiting to be sent, or because
+// the asynchronous queue of this Log was full.
+func (e *endpoint) Dropped() uint64 {
+	return e.stream.Dropped() + atomic.LoadUint64(&e.p.d)
+}
+func (p *poster) sendAll() error {
+	var err error
+	for {
+		p.m.Lock()
+		if len(p.q) == 0 {
+			p.q = nil
+			p.m.Unlock()
+			return err
+		}
+		v := p.q[0]
+		p.q[0] = batch{}
+		p.q, p.t = p.q[1:], p.t-v.n
+		p.m.Unlock()
+		if x := p.send(v); x != nil {
+			atomic.AddUint64(&p.d, uint64(v.n))
+			err = x
+		}
+	}
+}
+func (e *endpoint) With(v ...Field) Log {
+	if len(v) == 0 {
+		return e
+	}
+	return &endpoint{p: e.p, stream: *e.stream.With(v...).(*stream)}
+}
+func (e *endpoint) Fatal(m string, v ...interface{}) {
+	e.write(Fatal, 0, m, v)
+	if FatalExits {
+		e.Sync()
+		os.Exit(1)
+	}
+}
+func (p *poster) body(v batch) ([]byte, error) {
+	b := v.b
+	if p.p == JSONArray {
+		b = make([]byte, 1, len(v.b)+2)
+		b[0] = '['
+		for s, i := 0, 0; i < len(v.b); i++ {
+			if v.b[i] != '\n' {
+				continue
+			}
+			if i > s {
+				if len(b) > 1 {
+					b = append(b, ',')
+				}
+				b = append(b, v.b[s:i]...)
+			}
+			s = i + 1
+		}
+		b = append(b, ']')
+	}
+	if !p.z {
+		return b, nil
+	}
+	var (
+		o bytes.Buffer
+		w = gzip.NewWriter(&o)
+	)
+	if _, err := w.Write(b); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return o.Bytes(), nil
+}
+func (p *poster) send(v batch) error {
+	b, err := p.body(v)
+	if err != nil {
+		return err
+	}
+	d := p.b
+	for i := 0; ; i++ {
+		r, _ := http.NewRequest(http.MethodPost, p.u, bytes.NewReader(b))
+		if p.p == JSONArray {
+			r.Header.Set("Content-Type", "application/json")
+		} else {
+			r.Header.Set("Content-Type", "application/x-ndjson")
+		}
+		if p.z {
+			r.Header.Set("Content-Encoding", "gzip")
+		}
+		for k, x := range p.r {
+			r.Header[k] = x
+		}
+		w := d
+		x, err := p.h.Do(r)
+		if err == nil {
+			io.Copy(ioutil.Discard, x.Body)
+			x.Body.Close()
+			if x.StatusCode < 300 {
+				return nil
+			}
+			err = errors.New("unexpected response status: " + x.Status)
+			if x.StatusCode != http.StatusTooManyRequests && x.StatusCode < 500 {
+				return err
+			}
+			// NOTE(dij): Honor the server's Retry-After delay (in seconds) as long
+			//            as it's within the maximum backoff.
+			if n, e := strconv.Atoi(x.Header.Get("Retry-After")); e == nil && n > 0 {
+				if w = time.Duration(n) * time.Second; w > p.w {
+					w = p.w
+				}
+			}
+		}
+		if i >= httpRetries {
+			return err
+		}
+		t := time.NewTimer(w)
+		select {
+		case <-p.x:
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+		if d *= 2; d > p.w {
+			d = p.w
+		}
+	}
+}
+func (p *poster) Write(b []byte) (int, error) {
+	p.m.Lock()
+	if p.o {
+		p.m.Unlock()
+		return 0, os.ErrClosed
+	}
+	p.c.b = append(p.c.b, b...)
+	if p.c.n++; p.c.n >= p.n || len(p.c.b) >= p.s {
+		p.queue()
+		select {
+		case p.k <- struct{}{}:
+		default:
+		}
+	}
+	p.m.Unlock()
+	return len(b), nil
+}
+func (Payload) setting() setting {
+	return setPayload
+}
+func (settingBatch) setting() setting {
+	return setBatch
+}
+func (settingHeader) setting() setting {
+	return setHeader
+}
@@ -0,0 +1,194 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"sync"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+type testServer struct {
+	c []int
+	b [][]byte
+	h []http.Header
+	m sync.Mutex
+}
+
+func (s *testServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var x io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		z, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		x = z
+	}
+	b, _ := ioutil.ReadAll(x)
+	s.m.Lock()
+	s.b, s.h = append(s.b, b), append(s.h, r.Header.Clone())
+	c := http.StatusOK
+	if len(s.c) > 0 {
+		c, s.c = s.c[0], s.c[1:]
+	}
+	s.m.Unlock()
+	w.WriteHeader(c)
+}
+func (s *testServer) bodies() [][]byte {
+	s.m.Lock()
+	b := append([][]byte(nil), s.b...)
+	s.m.Unlock()
+	return b
+}
+func TestHTTPAlign(t *testing.T) {
+	// NOTE(dij): 64-bit atomic operations panic on 32-bit platforms if the
+	//            value isn't 8-byte aligned.
+	if o := unsafe.Offsetof(poster{}.d); o%8 != 0 {
+		t.Fatalf("poster.d is at offset %d, which is not 8-byte aligned", o)
+	}
+}
+func TestHTTPBatch(t *testing.T) {
+	var (
+		s = new(testServer)
+		h = httptest.NewServer(s)
+	)
+	defer h.Close()
+	l, err := HTTP(h.URL, Info, Batch(2, 0, time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.(io.Closer).Close()
+	l.Info("one")
+	l.Info("two")
+	l.Info("three")
+	if err = l.(syncer).Sync(); err != nil {
+		t.Fatal(err)
+	}
+	b := s.bodies()
+	if len(b) != 2 {
+		t.Fatalf("server received %d requests, expected 2", len(b))
+	}
+	if n := bytes.Count(b[0], []byte("\n")); n != 2 {
+		t.Fatalf("first batch contains %d entries, expected 2", n)
+	}
+	if n := bytes.Count(b[1], []byte("\n")); n != 1 {
+		t.Fatalf("second batch contains %d entries, expected 1", n)
+	}
+	if c := s.h[0].Get("Content-Type"); c != "application/x-ndjson" {
+		t.Fatalf("unexpected Content-Type %q", c)
+	}
+}
+func TestHTTPRetry(t *testing.T) {
+	var (
+		s = &testServer{c: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}}
+		h = httptest.NewServer(s)
+	)
+	defer h.Close()
+	l, err := HTTP(h.URL, Info, Backoff(time.Millisecond*10, time.Millisecond*20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.(io.Closer).Close()
+	l.Info("retry")
+	if err = l.(syncer).Sync(); err != nil {
+		t.Fatalf("Sync returned an error after retrying: %s", err)
+	}
+	if b := s.bodies(); len(b) != 3 || !bytes.Equal(b[0], b[2]) {
+		t.Fatalf("server received %d requests, expected 3 matching requests", len(b))
+	}
+	if d := l.(interface{ Dropped() uint64 }).Dropped(); d != 0 {
+		t.Fatalf("Log dropped %d entries, expected zero", d)
+	}
+	s.m.Lock()
+	s.c = []int{http.StatusBadRequest}
+	s.m.Unlock()
+	l.Info("rejected")
+	if err = l.(syncer).Sync(); err == nil {
+		t.Fatal("Sync did not return an error for a rejected request")
+	}
+	if b := s.bodies(); len(b) != 4 {
+		t.Fatalf("server received %d requests, expected a 4xx to not be retried", len(b))
+	}
+	if d := l.(interface{ Dropped() uint64 }).Dropped(); d != 1 {
+		t.Fatalf("Log dropped %d entries, expected one", d)
+	}
+}
+func TestHTTPJSONArray(t *testing.T) {
+	var (
+		s = new(testServer)
+		h = httptest.NewServer(s)
+	)
+	defer h.Close()
+	l, err := HTTP(h.URL, Info, JSONArray, Compress, Header("X-Test", "value"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Info("one")
+	l.With(F("key", "value")).Warning("two")
+	if err = l.(io.Closer).Close(); err != nil {
+		t.Fatal(err)
+	}
+	b := s.bodies()
+	if len(b) != 1 {
+		t.Fatalf("server received %d requests, expected 1", len(b))
+	}
+	var v []map[string]interface{}
+	if err = json.Unmarshal(b[0], &v); err != nil {
+		t.Fatalf("body %q is not a JSON array: %s", b[0], err)
+	}
+	if len(v) != 2 || v[1]["key"] != "value" {
+		t.Fatalf("unexpected body %q", b[0])
+	}
+	if s.h[0].Get("Content-Type") != "application/json" || s.h[0].Get("X-Test") != "value" {
+		t.Fatalf("unexpected headers %v", s.h[0])
+	}
+	if err = l.(syncer).Sync(); err == nil {
+		t.Fatal("Sync did not return an error after Close")
+	}
+}
+func TestHTTPFatal(t *testing.T) {
+	if u := os.Getenv("LOGX_TEST_URL"); len(u) > 0 {
+		l, _ := HTTP(u, Batch(0, 0, time.Hour))
+		l.Warning("before")
+		l.Fatal("exiting")
+		return
+	}
+	var (
+		s = new(testServer)
+		h = httptest.NewServer(s)
+	)
+	defer h.Close()
+	c := exec.Command(os.Args[0], "-test.run=^TestHTTPFatal$")
+	c.Env = append(os.Environ(), "LOGX_TEST_URL="+h.URL)
+	if err := c.Run(); err == nil {
+		t.Fatal("Fatal did not exit the process")
+	}
+	b := bytes.Join(s.bodies(), nil)
+	if !bytes.Contains(b, []byte("before")) || !bytes.Contains(b, []byte("exiting")) {
+		t.Fatalf("server did not receive the entries before exit: %q", b)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultJournal is the path to the systemd-journald native protocol socket
+// used by journal backed Logs when the 'JournalSocket' option is not specified.
+const DefaultJournal = "/run/systemd/journal/socket"
+
+type journal struct{}
+type settingSocket string
+
+// JournalSocket will create an Option interface that sets the path of the socket
+// that a journal backed Log will write to. This can be used to write to a test
+// socket instead of the systemd-journald socket.
+//
+// This setting has no effect on non-journal backed logging instances.
+func JournalSocket(p string) Option {
+	return settingSocket(p)
+}
+func (settingSocket) setting() setting {
+	return setSocket
+}
+func valueString(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case error:
+		return x.Error()
+	case fmt.Stringer:
+		return x.String()
+	}
+	return fmt.Sprint(v)
+}
+func appendJournalKey(b []byte, k string) []byte {
+	k = strings.TrimLeft(k, "_")
+	if len(k) == 0 {
+		return append(b, 'X')
+	}
+	if k[0] >= '0' && k[0] <= '9' {
+		b = append(b, 'X', '_')
+	}
+	for i := 0; i < len(k) && i < 64; i++ {
+		switch c := k[i]; {
+		case c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+			b = append(b, c)
+		case c >= 'a' && c <= 'z':
+			b = append(b, c-0x20)
+		default:
+			b = append(b, '_')
+		}
+	}
+	return b
+}
+func appendJournalField(b []byte, k, v string) []byte {
+	if b = appendJournalKey(b, k); strings.IndexByte(v, '\n') == -1 {
+		b = append(append(b, '='), v...)
+		return append(b, '\n')
+	}
+	var n [8]byte
+	binary.LittleEndian.PutUint64(n[:], uint64(len(v)))
+	b = append(append(append(b, '\n'), n[:]...), v...)
+	return append(b, '\n')
+}
+func (journal) Format(b []byte, f uint8, r Record) []byte {
+	m := r.Message
+	if len(m) > 0 && m[len(m)-1] == '\n' {
+		m = m[:len(m)-1]
+	}
+	b = appendJournalField(b, "MESSAGE", m)
+	b = append(append(b, "PRIORITY="...), byte('0'+severity(r.Level)), '\n')
+	if len(r.Prefix) > 0 {
+		b = appendJournalField(b, "SYSLOG_IDENTIFIER", r.Prefix)
+	}
+	if f&(FlagFileLong|FlagFileShort) != 0 {
+		b = appendJournalField(b, "CODE_FILE", r.File)
+		b = append(strconv.AppendInt(append(b, "CODE_LINE="...), int64(r.Line), 10), '\n')
+	}
+	for i := range r.Fields {
+		b = appendJournalField(b, r.Fields[i].Key, valueString(r.Fields[i].Value))
+	}
+	return b
+}
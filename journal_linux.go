@@ -0,0 +1,210 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build linux
+// +build linux
+
+package logx
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	memfdCloexec  = 0x1
+	memfdSealing  = 0x2
+	fcntlAddSeals = 0x409
+	sealAll       = 0xF
+)
+
+type journalConn struct {
+	c *net.UnixConn
+	a *net.UnixAddr
+	m sync.Mutex
+	x bool
+}
+
+// Journal will attempt to create a systemd-journald backed Log instance that
+// writes entries using the native journal protocol. Entries that are too large
+// to send as a single datagram are sent using a sealed memfd instead.
+//
+// Each entry includes the "MESSAGE", "PRIORITY", "SYSLOG_IDENTIFIER" (the prefix
+// of the Log, which defaults to the program name), "CODE_FILE" and "CODE_LINE"
+// fields. Any Fields are added with their keys converted to uppercase and any
+// invalid characters replaced with underscores.
+//
+// The 'FlagFileLong' flag is always set, unless 'FlagFileShort' is specified.
+// The other flags are ignored. The 'JournalSocket' option can be used to change
+// the socket path from 'DefaultJournal'.
+//
+// The returned Log supports the 'Close' function to close the socket.
+func Journal(o ...Option) (Log, error) {
+	var (
+		f = settingFlags(DefaultFlags)
+		s = settingSocket(DefaultJournal)
+		p bool
+	)
+	for i := range o {
+		if o[i] == nil {
+			continue
+		}
+		switch o[i].setting() {
+		case setFlags:
+			f, _ = o[i].(settingFlags)
+		case setSocket:
+			s, _ = o[i].(settingSocket)
+		case setPrefix:
+			p = true
+		}
+	}
+	if f&settingFlags(FlagFileShort) == 0 {
+		f |= settingFlags(FlagFileLong)
+	}
+	if !p {
+		o = append(o, Prefix(filepath.Base(os.Args[0])))
+	}
+	j := &journalConn{a: &net.UnixAddr{Name: string(s), Net: "unixgram"}}
+	if err := j.dial(); err != nil {
+		return nil, errors.New(`cannot connect to journal "` + string(s) + `": ` + err.Error())
+	}
+	return &remote{c: j, stream: *Writer(j, append(o, f, Format(journal{}))...).(*stream)}, nil
+}
+func memfdCall() uintptr {
+	switch runtime.GOARCH {
+	case "amd64":
+		return 319
+	case "386":
+		return 356
+	case "arm":
+		return 385
+	case "arm64", "riscv64", "loong64":
+		return 279
+	case "ppc64", "ppc64le":
+		return 360
+	case "s390x":
+		return 350
+	case "mips64", "mips64le":
+		return 5314
+	case "mips", "mipsle":
+		return 4354
+	}
+	return 0
+}
+func (j *journalConn) dial() error {
+	c, err := net.DialUnix("unixgram", nil, j.a)
+	if err != nil {
+		return err
+	}
+	j.c = c
+	return nil
+}
+func (j *journalConn) Close() error {
+	j.m.Lock()
+	var err error
+	if j.x = true; j.c != nil {
+		err = j.c.Close()
+		j.c = nil
+	}
+	j.m.Unlock()
+	return err
+}
+func tempfd(b []byte) (*os.File, error) {
+	if n := memfdCall(); n > 0 {
+		v, _ := syscall.BytePtrFromString("logx-journal")
+		if r, _, e := syscall.Syscall(n, uintptr(unsafe.Pointer(v)), memfdCloexec|memfdSealing, 0); e == 0 {
+			f := os.NewFile(r, "logx-journal")
+			if _, err := f.Write(b); err != nil {
+				f.Close()
+				return nil, err
+			}
+			if _, _, e = syscall.Syscall(syscall.SYS_FCNTL, r, fcntlAddSeals, sealAll); e != 0 {
+				f.Close()
+				return nil, e
+			}
+			return f, nil
+		}
+	}
+	// NOTE(dij): Fallback to an unlinked temp file if memfd isn't supported,
+	//            journald will accept any regular file.
+	f, err := ioutil.TempFile("/dev/shm", "logx-journal-")
+	if err != nil {
+		return nil, err
+	}
+	os.Remove(f.Name())
+	if _, err = f.Write(b); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+func (j *journalConn) writeFd(b []byte) error {
+	f, err := tempfd(b)
+	if err != nil {
+		return err
+	}
+	r, err := j.c.SyscallConn()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	// NOTE(dij): WriteMsgUnix refuses to write on connected datagram sockets,
+	//            so the rights message is sent with the raw descriptor instead.
+	var e error
+	err = r.Write(func(v uintptr) bool {
+		e = syscall.Sendmsg(int(v), nil, syscall.UnixRights(int(f.Fd())), nil, 0)
+		return e != syscall.EAGAIN
+	})
+	if f.Close(); err != nil {
+		return err
+	}
+	return e
+}
+func (j *journalConn) Write(b []byte) (int, error) {
+	j.m.Lock()
+	if j.x {
+		j.m.Unlock()
+		return 0, os.ErrClosed
+	}
+	var err error
+	for i := 0; i < 2; i++ {
+		if j.c == nil {
+			if err = j.dial(); err != nil {
+				break
+			}
+		}
+		if _, err = j.c.Write(b); err == nil {
+			break
+		}
+		if errors.Is(err, syscall.EMSGSIZE) || errors.Is(err, syscall.ENOBUFS) {
+			err = j.writeFd(b)
+			break
+		}
+		j.c.Close()
+		j.c = nil
+	}
+	j.m.Unlock()
+	if err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
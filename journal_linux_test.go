@@ -0,0 +1,116 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build linux
+// +build linux
+
+package logx
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func testJournal(t *testing.T) (*net.UnixConn, Log) {
+	p := filepath.Join(tempDir(t), "socket")
+	c, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: p, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := Journal(JournalSocket(p), Prefix("test"))
+	if err != nil {
+		c.Close()
+		t.Fatal(err)
+	}
+	return c, l
+}
+func TestJournalDatagram(t *testing.T) {
+	c, l := testJournal(t)
+	defer c.Close()
+	defer l.(io.Closer).Close()
+	l.With(F("request.id", 10)).Error("hello %s", "world")
+	b := make([]byte, 4096)
+	c.SetReadDeadline(time.Now().Add(time.Second * 5))
+	n, err := c.Read(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := make(map[string]string)
+	for _, s := range strings.Split(strings.TrimSuffix(string(b[:n]), "\n"), "\n") {
+		if i := strings.IndexByte(s, '='); i > 0 {
+			v[s[:i]] = s[i+1:]
+		}
+	}
+	for k, x := range map[string]string{"MESSAGE": "hello world", "PRIORITY": "3", "SYSLOG_IDENTIFIER": "test", "REQUEST_ID": "10"} {
+		if v[k] != x {
+			t.Fatalf("field %s is %q, expected %q", k, v[k], x)
+		}
+	}
+	if !strings.HasSuffix(v["CODE_FILE"], "journal_linux_test.go") || len(v["CODE_LINE"]) == 0 {
+		t.Fatalf("unexpected caller %s:%s", v["CODE_FILE"], v["CODE_LINE"])
+	}
+}
+func TestJournalMemfd(t *testing.T) {
+	c, l := testJournal(t)
+	defer c.Close()
+	defer l.(io.Closer).Close()
+	m := strings.Repeat("a", 1<<20)
+	l.Error(m)
+	var (
+		b = make([]byte, 4096)
+		o = make([]byte, syscall.CmsgSpace(4))
+	)
+	c.SetReadDeadline(time.Now().Add(time.Second * 5))
+	n, k, _, _, err := c.ReadMsgUnix(b, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Fatalf("datagram contains %d bytes, expected zero", n)
+	}
+	x, err := syscall.ParseSocketControlMessage(o[:k])
+	if err != nil || len(x) != 1 {
+		t.Fatalf("cannot parse control message: %v", err)
+	}
+	d, err := syscall.ParseUnixRights(&x[0])
+	if err != nil || len(d) != 1 {
+		t.Fatalf("cannot parse rights: %v", err)
+	}
+	f := os.NewFile(uintptr(d[0]), "memfd")
+	defer f.Close()
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	v, err := ioutil.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(v, []byte("MESSAGE="+m+"\n")) {
+		t.Fatalf("memfd contains %d bytes without the expected message", len(v))
+	}
+}
+func TestJournalMissing(t *testing.T) {
+	if _, err := Journal(JournalSocket(filepath.Join(tempDir(t), "missing"))); err == nil {
+		t.Fatal("Journal did not return an error for a missing socket")
+	}
+}
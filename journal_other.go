@@ -0,0 +1,30 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build !linux
+// +build !linux
+
+package logx
+
+import "errors"
+
+// Journal will attempt to create a systemd-journald backed Log instance that
+// writes entries using the native journal protocol.
+//
+// This platform does not support systemd-journald, so this function always
+// returns an error.
+func Journal(_ ...Option) (Log, error) {
+	return nil, errors.New("journal is not supported on this platform")
+}
@@ -0,0 +1,89 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import "sync/atomic"
+
+// AtomicLevel is a handle to a Level that can be safely read and changed while
+// it is being used. Copies of an AtomicLevel refer to the same Level.
+//
+// An AtomicLevel can be used as an Option when creating a Log, which will cause
+// the Log to use the AtomicLevel as its Level. Multiple Logs created with the
+// same AtomicLevel will all be changed when the AtomicLevel is changed, either
+// by calling 'SetLevel' on the AtomicLevel or on any of the Logs.
+//
+// Use the 'NewAtomicLevel' function to create an AtomicLevel. The empty value of
+// an AtomicLevel reports the 'Warning' Level and cannot be changed, except by the
+// 'UnmarshalText' function. Using it as an Option has no effect.
+type AtomicLevel struct {
+	v *uint32
+}
+
+// NewAtomicLevel returns a new AtomicLevel set to the Level 'l'.
+func NewAtomicLevel(l Level) AtomicLevel {
+	v := uint32(l)
+	return AtomicLevel{v: &v}
+}
+
+// Level returns the current Level of this AtomicLevel. The empty value of an
+// AtomicLevel will return 'Warning'.
+func (a AtomicLevel) Level() Level {
+	if a.v == nil {
+		return Warning
+	}
+	return Level(atomic.LoadUint32(a.v))
+}
+
+// SetLevel changes the current Level of this AtomicLevel. This function does
+// nothing on the empty value of an AtomicLevel.
+func (a AtomicLevel) SetLevel(l Level) {
+	if a.v == nil {
+		return
+	}
+	atomic.StoreUint32(a.v, uint32(l))
+}
+
+// String returns the lowercase name of the current Level of this AtomicLevel.
+func (a AtomicLevel) String() string {
+	return a.Level().name()
+}
+func (AtomicLevel) setting() setting {
+	return setAtomic
+}
+
+// MarshalText returns the lowercase name of the current Level of this AtomicLevel.
+// This fulfills the 'encoding.TextMarshaler' interface.
+func (a AtomicLevel) MarshalText() ([]byte, error) {
+	return []byte(a.Level().name()), nil
+}
+
+// UnmarshalText changes the current Level of this AtomicLevel to the Level name
+// or number in the supplied text. An error is returned if the text is not a valid
+// Level. This fulfills the 'encoding.TextUnmarshaler' interface.
+//
+// If this AtomicLevel is empty, a new Level is created.
+func (a *AtomicLevel) UnmarshalText(b []byte) error {
+	l, err := ParseLevel(string(b))
+	if err != nil {
+		return err
+	}
+	if a.v == nil {
+		*a = NewAtomicLevel(l)
+		return nil
+	}
+	a.SetLevel(l)
+	return nil
+}
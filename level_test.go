@@ -0,0 +1,73 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+)
+
+func TestAtomicLevelEmpty(t *testing.T) {
+	var a AtomicLevel
+	if a.Level() != Warning {
+		t.Fatalf("empty AtomicLevel returned %s, expected Warning", a.Level())
+	}
+	a.SetLevel(Debug)
+	if a.String() != "warn" {
+		t.Fatalf("empty AtomicLevel returned %s, expected Warning", a.String())
+	}
+	b, err := json.Marshal(struct{ L AtomicLevel }{})
+	if err != nil {
+		t.Fatalf("marshal returned an error: %s", err)
+	}
+	if string(b) != `{"L":"warn"}` {
+		t.Fatalf("marshal returned %s", b)
+	}
+	var v struct{ L AtomicLevel }
+	if err = json.Unmarshal([]byte(`{"L":"debug"}`), &v); err != nil {
+		t.Fatalf("unmarshal returned an error: %s", err)
+	}
+	if v.L.Level() != Debug {
+		t.Fatalf("unmarshal returned %s, expected Debug", v.L.Level())
+	}
+}
+func TestWithLevels(t *testing.T) {
+	var (
+		p = Writer(ioutil.Discard, Info)
+		c = p.With(F("key", "value"))
+	)
+	c.SetLevel(Debug)
+	c.SetPrintLevel(Error)
+	if l, k := levelsOf(p); l != Info || k != Info {
+		t.Fatalf("parent Levels changed to %s, %s", l, k)
+	}
+	if l, k := levelsOf(c); l != Debug || k != Error {
+		t.Fatalf("child Levels are %s, %s, expected Debug, Error", l, k)
+	}
+	a := NewAtomicLevel(Info)
+	p = Writer(ioutil.Discard, a)
+	p.With(F("key", "value")).SetLevel(Debug)
+	if a.Level() != Debug {
+		t.Fatalf("shared AtomicLevel returned %s, expected Debug", a.Level())
+	}
+	r := Routes()
+	r.SetLevel(Info)
+	r.With(F("key", "value")).SetLevel(Debug)
+	if l, _ := levelsOf(r); l != Info {
+		t.Fatalf("Router Level changed to %s", l)
+	}
+}
@@ -16,8 +16,12 @@
 package logx
 
 import (
+	"errors"
+	"fmt"
 	"io"
 	"runtime"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -111,6 +115,8 @@ const (
 	LstdFlags           = FlagStandard
 )
 
+const bufMax = 64 << 10
+
 // FatalExits is a boolean setting that determines if a call to Fatal or LogFatal
 // will exit the program using 'os.Exit(1)'. If this is set to false, a call to
 // Fatal or LogFatal will continue program execution after being called.
@@ -118,11 +124,26 @@ const (
 // The default value is true.
 var FatalExits = true
 
+var bufs = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 256)
+		return &b
+	},
+}
+
 // Level is an alias of a byte that represents the current Log level.
 type Level uint8
 
 // Log is an interface for any type of struct that supports standard Logging
 // functions.
+//
+// Messages below the Level of a Log are discarded before they are formatted. A
+// call that is discarded does not allocate, except when it has arguments and is
+// made through this interface. The Go compiler cannot prove that the arguments
+// do not escape an interface call, so the variadic slice and any non-constant
+// arguments that are not pointers are allocated by the caller, even if the message
+// is discarded. This can be avoided by checking the Level of a shared AtomicLevel
+// before making the call.
 type Log interface {
 	// SetLevel changes the current logging level of this Log.
 	SetLevel(Level)
@@ -130,6 +151,14 @@ type Log interface {
 	SetPrefix(string)
 	// SetPrintLevel sets the logging level used when 'Print*' statements are called.
 	SetPrintLevel(Level)
+	// With returns a child Log that will include the supplied Fields with every
+	// message written. Any Fields already attached to this Log are carried into
+	// the returned Log.
+	//
+	// The returned Log shares the same output as this Log. It starts with the
+	// same Levels as this Log, but changing them does not affect this Log (unless
+	// the Level is a shared AtomicLevel).
+	With(...Field) Log
 	// Print writes a message to the logger.
 	//
 	// The function arguments are similar to 'fmt.Sprint' and 'fmt.Print'. The only
@@ -223,10 +252,18 @@ type Log interface {
 	// format string.
 	Warning(string, ...interface{})
 }
+type syncer interface {
+	Sync() error
+}
+type leveler interface {
+	levels() (Level, Level)
+}
 type logger struct {
-	m sync.Mutex
+	e Formatter
 	w io.Writer
-	p []byte
+	q *queue
+	p string
+	m sync.Mutex
 	f uint8
 }
 
@@ -270,11 +307,8 @@ func (l Level) String() string {
 	return "INVAL"
 }
 func (l *logger) SetPrefix(p string) {
-	if l.m.Lock(); len(p) == 0 {
-		l.p = nil
-	} else {
-		l.p = []byte(p)
-	}
+	l.m.Lock()
+	l.p = p
 	l.m.Unlock()
 }
 func itoa(b *[28]byte, p, i, w int) int {
@@ -307,6 +341,33 @@ func Normal(req int, normal Level) Level {
 	return Level(req)
 }
 
+// ParseLevel will attempt to convert the supplied string into a Level. The string
+// may be the name of a Level (such as "info" or "warn", ignoring case) or the
+// numeric value of a Level. An error is returned if the string is not a valid
+// Level.
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "trace":
+		return Trace, nil
+	case "debug":
+		return Debug, nil
+	case "info":
+		return Info, nil
+	case "warn", "warning":
+		return Warning, nil
+	case "error":
+		return Error, nil
+	case "fatal":
+		return Fatal, nil
+	case "panic":
+		return Panic, nil
+	}
+	if v, err := strconv.ParseUint(strings.TrimSpace(s), 10, 8); err == nil && v <= uint64(Panic) {
+		return Level(v), nil
+	}
+	return invalidLevel, errors.New(`invalid level "` + s + `"`)
+}
+
 // NormalUint will attempt to normalize the requested log level. This will check
 // the supplied integer and will return it as a valid log level if in bounds of
 // the supported log levels. If not, the specified normal log level will be
@@ -319,78 +380,119 @@ func NormalUint(req uint, normal Level) Level {
 	return Level(req)
 }
 func (l *logger) Output(d int, s string) error {
-	var (
-		f string
-		p int
-	)
+	return l.log(d+1, invalidLevel, nil, s)
+}
+func (l *logger) log(d int, v Level, x []Field, s string) error {
+	r := Record{Level: v, Fields: x, Message: s}
 	if l.f&(FlagFileLong|FlagFileShort) != 0 {
 		var ok bool
-		if _, f, p, ok = runtime.Caller(d); !ok {
-			f, p = "??", 0
+		if _, r.File, r.Line, ok = runtime.Caller(d); !ok {
+			r.File, r.Line = "??", 0
+		}
+		if l.f&FlagFileShort != 0 {
+			for i := len(r.File) - 1; i > 0; i-- {
+				if r.File[i] == '/' {
+					r.File = r.File[i+1:]
+					break
+				}
+			}
 		}
 	}
+	if r.Time = time.Now(); l.f&FlagTimeUTC != 0 {
+		r.Time = r.Time.UTC()
+	}
 	var (
-		b [28]byte
-		n int
+		b   = bufs.Get().(*[]byte)
+		err error
 	)
 	l.m.Lock()
-	if t := time.Now(); l.f&(FlagDate|FlagTimeUTC|FlagTime|FlagMicroseconds) != 0 {
-		if l.f&FlagTimeUTC != 0 {
-			t = t.UTC()
-		}
-		if l.f&FlagDate != 0 {
-			y, m, d := t.Date()
-			n = itoa(&b, n, y, 4)
-			b[n] = '/'
-			n = itoa(&b, n+1, int(m), 2)
-			b[n] = '/'
-			n = itoa(&b, n+1, d, 2)
-			b[n] = ' '
-			n++
-		}
-		if l.f&(FlagTime|FlagMicroseconds) != 0 {
-			h, m, v := t.Clock()
-			n = itoa(&b, n, h, 2)
-			b[n] = ':'
-			n = itoa(&b, n+1, m, 2)
-			b[n] = ':'
-			n = itoa(&b, n+1, v, 2)
-			if l.f&FlagMicroseconds != 0 {
-				b[n] = '.'
-				n = itoa(&b, n+1, t.Nanosecond()/1e3, 6)
-			}
-			b[n] = ' '
-			n++
+	r.Prefix = l.p
+	if *b = l.e.Format((*b)[:0], l.f, r); l.q != nil {
+		err = l.q.push(b)
+	} else {
+		_, err = l.w.Write(*b)
+		put(b)
+	}
+	l.m.Unlock()
+	return err
+}
+func put(b *[]byte) {
+	// NOTE(dij): Don't keep large buffers around, as a single large entry would
+	//            hold onto that memory for the lifetime of the pool.
+	if cap(*b) > bufMax {
+		return
+	}
+	bufs.Put(b)
+}
+func logAs(x Log, l Level, c int, m string, v []interface{}) {
+	if w, ok := x.(LogWriter); ok {
+		w.Log(l, c+1, m, v...)
+		return
+	}
+	if len(m) == 0 {
+		m, v = "%s", []interface{}{fmt.Sprint(v...)}
+	}
+	switch l {
+	case Trace:
+		x.Trace(m, v...)
+	case Debug:
+		x.Debug(m, v...)
+	case Info:
+		x.Info(m, v...)
+	case Warning:
+		x.Warning(m, v...)
+	case Print:
+		x.Printf(m, v...)
+	default:
+		// NOTE(dij): Write as Error here to prevent the non-flexable logger
+		//            from exiting the program before all logs can be written.
+		x.Error(m, v...)
+	}
+}
+func logGlobal(l Level, m string, v []interface{}) {
+	// NOTE(dij): Copy the arguments, so that the slice passed to the calling
+	//            function doesn't escape and filtered out calls don't allocate.
+	logAs(Global, l, 2, m, append([]interface{}(nil), v...))
+}
+func levelsOf(l Log) (Level, Level) {
+	if x, ok := l.(leveler); ok {
+		return x.levels()
+	}
+	return invalidLevel, invalidLevel
+}
+func enabled(x Log, l Level) bool {
+	v, p := levelsOf(x)
+	if l == Print {
+		if p >= invalidLevel {
+			return true
 		}
+		l = p
 	}
-	o := make([]byte, n+len(s)+len(f)+len(l.p)+30)
-	if copy(o, b[:n]); l.f&(FlagFileLong|FlagFileShort) != 0 {
-		if l.f&FlagFileShort != 0 {
-			for i := len(f) - 1; i > 0; i-- {
-				if f[i] == '/' {
-					f = f[i+1:]
-					break
-				}
-			}
+	return v >= invalidLevel || l >= v
+}
+func message(m string, v []interface{}) string {
+	switch {
+	case len(m) == 0 && len(v) == 1:
+		if s, ok := v[0].(string); ok {
+			return s
 		}
-		n += copy(o[n:], f)
-		o[n] = ':'
-		n++
-		c := itoa(&b, 0, p, -1)
-		b[c] = ' '
-		n += copy(o[n:], b[:c+1])
+		return fmt.Sprint(v...)
+	case len(m) == 0:
+		return fmt.Sprint(v...)
+	case len(v) == 0 && strings.IndexByte(m, '%') == -1:
+		return m
 	}
-	if len(l.p) > 0 {
-		n += copy(o[n:], l.p)
-		o[n] = ' '
-		n++
+	return fmt.Sprintf(m, v...)
+}
+func (l *logger) flush() {
+	if l.m.Lock(); l.q != nil && l.q.c != nil {
+		l.q.g.Wait()
 	}
-	if n += copy(o[n:], s); len(s) == 0 || o[n-1] != '\n' {
-		o[n] = '\n'
-		n++
+	l.m.Unlock()
+}
+func (l *logger) close() {
+	if l.m.Lock(); l.q != nil {
+		l.q.close()
 	}
-	_, err := l.w.Write(o[:n])
 	l.m.Unlock()
-	o = nil
-	return err
 }
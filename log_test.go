@@ -0,0 +1,112 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocations are not accurate with the race detector")
+	}
+	// NOTE(dij): See the Log interface documentation for the allocation made
+	//            by filtered calls with arguments.
+	var (
+		l = Writer(ioutil.Discard, Warning)
+		s = l.(*stream)
+	)
+	if n := testing.AllocsPerRun(100, func() { l.Debug("hello") }); n != 0 {
+		t.Fatalf("filtered call made %.0f allocations, expected zero", n)
+	}
+	if n := testing.AllocsPerRun(100, func() { s.Debug("hello %d %s", 1000, "x") }); n != 0 {
+		t.Fatalf("filtered call made %.0f allocations, expected zero", n)
+	}
+	if n := testing.AllocsPerRun(100, func() { l.Debug("hello %s", "x") }); n > 1 {
+		t.Fatalf("filtered call made %.0f allocations, expected at most one", n)
+	}
+	if n := testing.AllocsPerRun(100, func() { l.Warning("hello") }); n != 0 {
+		t.Fatalf("emitted call made %.0f allocations, expected zero", n)
+	}
+	if n := testing.AllocsPerRun(100, func() { l.Warning("hello %s", "x") }); n > 2 {
+		t.Fatalf("emitted call made %.0f allocations, expected at most two", n)
+	}
+}
+func BenchmarkFiltered(b *testing.B) {
+	l := Writer(ioutil.Discard, Warning)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Debug("hello %s", "x")
+	}
+}
+
+func BenchmarkFilteredNoArgs(b *testing.B) {
+	l := Writer(ioutil.Discard, Warning)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Debug("hello")
+	}
+}
+func BenchmarkFilteredConcrete(b *testing.B) {
+	l := Writer(ioutil.Discard, Warning).(*stream)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Debug("hello %d %s", i, "x")
+	}
+}
+func BenchmarkFilteredFields(b *testing.B) {
+	l := Writer(ioutil.Discard, Warning).With(F("key", "value"))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Debug("hello %s", "x")
+	}
+}
+func BenchmarkEmitted(b *testing.B) {
+	l := Writer(ioutil.Discard, Warning)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Warning("hello")
+	}
+}
+func BenchmarkEmittedFormat(b *testing.B) {
+	l := Writer(ioutil.Discard, Warning)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Warning("hello %s", "x")
+	}
+}
+func BenchmarkEmittedFields(b *testing.B) {
+	l := Writer(ioutil.Discard, Warning).With(F("key", "value"), F("n", 1))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Warning("hello %s", "x")
+	}
+}
+func BenchmarkEmittedJSON(b *testing.B) {
+	l := Writer(ioutil.Discard, Warning, Format(JSON)).With(F("key", "value"))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Warning("hello %s", "x")
+	}
+}
+func BenchmarkEmittedCaller(b *testing.B) {
+	l := Writer(ioutil.Discard, Warning, Flags(int(FlagStandard|FlagFileShort)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Warning("hello %s", "x")
+	}
+}
@@ -17,6 +17,7 @@ package logx
 
 import (
 	"fmt"
+	"io"
 	"os"
 )
 
@@ -60,6 +61,80 @@ func (m Multi) SetPrintLevel(n Level) {
 	}
 }
 
+func (m Multi) levels() (Level, Level) {
+	// NOTE(dij): Return the lowest Levels of the Log instances in this Multi,
+	//            as a message is written if any of them would write it. If any
+	//            Level is unknown, then the Levels of this Multi are unknown.
+	l, k := invalidLevel, invalidLevel
+	for i := range m {
+		v, p := levelsOf(m[i])
+		if v >= invalidLevel || p >= invalidLevel {
+			return invalidLevel, invalidLevel
+		}
+		if v < l {
+			l = v
+		}
+		if p < k {
+			k = p
+		}
+	}
+	return l, k
+}
+
+// With returns a Multi that contains a child Log for each Log instance in this
+// Multi, with each child including the supplied Fields.
+func (m Multi) With(f ...Field) Log {
+	n := make(Multi, len(m))
+	for i := range m {
+		n[i] = m[i].With(f...)
+	}
+	return &n
+}
+
+// Sync will call the 'Sync' function on each Log instance in this Multi that
+// supports it, such as file backed Logs.
+//
+// The first error encountered will be returned after all Logs are synced.
+func (m Multi) Sync() error {
+	var err error
+	for i := range m {
+		if x, ok := m[i].(syncer); ok {
+			if e := x.Sync(); e != nil && err == nil {
+				err = e
+			}
+		}
+	}
+	return err
+}
+
+// Close will call the 'Close' function on each Log instance in this Multi that
+// supports it, such as file backed Logs.
+//
+// The first error encountered will be returned after all Logs are closed.
+func (m Multi) Close() error {
+	var err error
+	for i := range m {
+		if x, ok := m[i].(io.Closer); ok {
+			if e := x.Close(); e != nil && err == nil {
+				err = e
+			}
+		}
+	}
+	return err
+}
+
+// Log fulfills the LogWriter interface and writes the message to each Log
+// instance in this Multi using the Level 'l'.
+//
+// Log instances that are not LogWriters will have the matching Log function
+// called instead, with the 'Fatal' and 'Panic' Levels written as 'Error' to
+// prevent exiting before all logs are written.
+func (m Multi) Log(l Level, c int, s string, v ...interface{}) {
+	for i := range m {
+		logAs(m[i], l, c+1, s, v)
+	}
+}
+
 // Print writes a message to the logger.
 //
 // The function arguments are similar to 'fmt.Sprint' and 'fmt.Print'. The only
@@ -93,6 +168,7 @@ func (m Multi) Panic(v ...interface{}) {
 			m[i].Error("", v...)
 		}
 	}
+	m.Sync()
 	panic(fmt.Sprint(v...))
 }
 
@@ -129,6 +205,7 @@ func (m Multi) Panicln(v ...interface{}) {
 			m[i].Error("", v...)
 		}
 	}
+	m.Sync()
 	panic(fmt.Sprint(v...))
 }
 
@@ -183,6 +260,7 @@ func (m Multi) Fatal(s string, v ...interface{}) {
 		}
 	}
 	if FatalExits {
+		m.Sync()
 		os.Exit(1)
 	}
 }
@@ -255,6 +333,7 @@ func (m Multi) Panicf(s string, v ...interface{}) {
 			m[i].Error(s, v...)
 		}
 	}
+	m.Sync()
 	panic(fmt.Sprintf(s, v...))
 }
 
@@ -0,0 +1,71 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+type countLog struct {
+	nop
+	n int
+}
+
+func (c *countLog) Info(_ string, _ ...interface{}) {
+	c.n++
+}
+func (c *countLog) Log(l Level, _ int, _ string, _ ...interface{}) {
+	if l == Info {
+		c.n++
+	}
+}
+
+func TestMultiLevels(t *testing.T) {
+	if l, k := levelsOf(Multiple()); l != invalidLevel || k != invalidLevel {
+		t.Fatalf("empty Multi returned %s, %s", l, k)
+	}
+	m := Multiple(Writer(ioutil.Discard, Error, PrintLevel(Warning)), Writer(ioutil.Discard, Info))
+	if l, k := levelsOf(m); l != Info || k != Info {
+		t.Fatalf("Multi returned %s, %s, expected Info, Info", l, k)
+	}
+	if l, _ := levelsOf(NewHolder(m)); l != Info {
+		t.Fatalf("Holder returned %s, expected Info", l)
+	}
+	if !enabled(m, Info) || enabled(m, Debug) {
+		t.Fatal("Multi reported the wrong enabled Levels")
+	}
+}
+func TestMultiUnknownLevels(t *testing.T) {
+	var (
+		b bytes.Buffer
+		c = new(countLog)
+		m = Multiple(Writer(&b, Error), c)
+	)
+	if l, k := levelsOf(m); l != invalidLevel || k != invalidLevel {
+		t.Fatalf("Multi returned %s, %s, expected unknown Levels", l, k)
+	}
+	Sample(m, time.Hour, 10, 0).Info("sampled")
+	Dedup(m, time.Hour).Info("collapsed")
+	if c.n != 2 {
+		t.Fatalf("Log without Levels received %d messages, expected 2", c.n)
+	}
+	if b.Len() > 0 {
+		t.Fatalf("Log received a filtered message %q", b.String())
+	}
+}
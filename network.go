@@ -0,0 +1,361 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultBacklog = 1024
+	defaultTimeout = time.Second * 5
+	defaultBackoff = time.Millisecond * 500
+	maxBackoff     = time.Minute
+)
+
+type network struct {
+	stream
+	c *netConn
+}
+type netConn struct {
+	d uint64
+	t *tls.Config
+	c net.Conn
+	x chan struct{}
+	n string
+	a string
+	q [][]byte
+	v []byte
+	m sync.Mutex
+	o time.Duration
+	b time.Duration
+	w time.Duration
+	k int
+	r bool
+	s bool
+	f bool
+}
+type settingBackoff struct {
+	b, w time.Duration
+}
+type settingTimeout time.Duration
+type settingBacklog int
+
+// Network will create a network backed Log instance that writes entries to the
+// collector at the specified network and address. The network may be "tcp",
+// "udp", "tls", "unix", "unixgram" or "unixpacket" (or any of their IPv4/IPv6
+// variants).
+//
+// The connection is made by a background goroutine once the first entry is written,
+// so logging never waits on a connection attempt. While the connection is being
+// made, cannot be made or is dropped, entries are held in an in-memory backlog
+// while the goroutine attempts to reconnect using an exponential backoff. The
+// backlog is written once connected again. When the backlog is full the oldest
+// entries are discarded and counted by the 'Dropped' function.
+//
+// The 'Backoff', 'WriteTimeout', 'Backlog' and 'TLS' options can be used to change
+// the connection behavior. Entries written to datagram networks are sent one
+// per datagram without the trailing newline.
+//
+// The returned Log supports the 'Close', 'Sync' and 'Dropped' functions. An
+// error is only returned if the network or address are invalid.
+func Network(n, a string, o ...Option) (Log, error) {
+	switch n {
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6", "unix", "unixgram", "unixpacket", "tls":
+	default:
+		return nil, errors.New(`invalid network "` + n + `"`)
+	}
+	if len(a) == 0 {
+		return nil, errors.New("network address cannot be empty")
+	}
+	c := newConn(n, a, o)
+	return &network{c: c, stream: *Writer(c, o...).(*stream)}, nil
+}
+func newConn(n, a string, o []Option) *netConn {
+	c := &netConn{n: n, a: a, k: defaultBacklog, o: defaultTimeout, b: defaultBackoff, w: maxBackoff, x: make(chan struct{})}
+	for i := range o {
+		if o[i] == nil {
+			continue
+		}
+		switch o[i].setting() {
+		case setTLS:
+			t, _ := o[i].(settingTLS)
+			c.t = t.Config
+		case setBackoff:
+			if v, _ := o[i].(settingBackoff); v.b > 0 {
+				if c.b, c.w = v.b, v.w; c.w < c.b {
+					c.w = c.b
+				}
+			}
+		case setTimeout:
+			v, _ := o[i].(settingTimeout)
+			c.o = time.Duration(v)
+		case setBacklog:
+			v, _ := o[i].(settingBacklog)
+			c.k = int(v)
+		}
+	}
+	return c
+}
+
+// Backlog will create an Option interface that sets the maximum number of entries
+// a network backed Log will hold while disconnected. Values less than or equal
+// to zero disable the backlog and entries written while disconnected (including
+// while the first connection is being made) are dropped.
+//
+// The default value is 1024. This setting has no effect on non-network backed
+// logging instances.
+func Backlog(n int) Option {
+	return settingBacklog(n)
+}
+
+// WriteTimeout will create an Option interface that sets the deadline for each
+// write made by a network backed Log. A write that does not complete before the
+// deadline will cause the connection to be re-established. Values less than or
+// equal to zero disable the deadline.
+//
+// The default value is five seconds. This setting has no effect on non-network
+// backed logging instances.
+func WriteTimeout(d time.Duration) Option {
+	return settingTimeout(d)
+}
+
+// Backoff will create an Option interface that sets the minimum and maximum delay
+// between reconnect attempts made by a network backed Log. The delay starts at
+// the minimum and doubles after each failed attempt, up to the maximum.
+//
+// The default values are 500 milliseconds and one minute. This setting has no
+// effect on non-network backed logging instances.
+func Backoff(min, max time.Duration) Option {
+	return settingBackoff{b: min, w: max}
+}
+func (settingBackoff) setting() setting {
+	return setBackoff
+}
+func (settingTimeout) setting() setting {
+	return setTimeout
+}
+func (settingBacklog) setting() setting {
+	return setBacklog
+}
+
+// Sync will write any queued entries and the backlog. If the Log is not connected
+// and the backlog is not empty, a connection attempt is made first. An error is
+// returned if the connection could not be made or the backlog could not be written.
+func (n *network) Sync() error {
+	n.flush()
+	return n.c.Sync()
+}
+func (c *netConn) Sync() error {
+	c.m.Lock()
+	if c.c == nil && len(c.q) > 0 && !c.s {
+		c.m.Unlock()
+		// NOTE(dij): Dial without holding the lock, the same as 'reconnect'.
+		x, err := dial(c.n, c.a, c.t)
+		if err != nil {
+			return err
+		}
+		if c.m.Lock(); c.c == nil && !c.s {
+			c.c = x
+		} else {
+			x.Close()
+		}
+	}
+	var err error
+	if c.c != nil {
+		if err = c.flush(); err != nil {
+			c.drop()
+		}
+	}
+	c.m.Unlock()
+	return err
+}
+
+// Close will close the connection used by this Log. If this Log is asynchronous,
+// this will wait for all queued entries to be written first. Any entries left
+// in the backlog that cannot be written are counted as dropped. Any Log functions
+// called after this will not write any output.
+func (n *network) Close() error {
+	n.close()
+	return n.c.Close()
+}
+func (c *netConn) drop() {
+	c.c.Close()
+	c.c = nil
+	if !c.r && !c.s {
+		c.r = true
+		go c.reconnect(c.b)
+	}
+}
+func (c *netConn) Close() error {
+	c.m.Lock()
+	if c.s {
+		c.m.Unlock()
+		return nil
+	}
+	c.s = true
+	close(c.x)
+	var err error
+	if c.c != nil {
+		c.flush()
+		err = c.c.Close()
+		c.c = nil
+	}
+	atomic.AddUint64(&c.d, uint64(len(c.q)))
+	c.q = nil
+	c.m.Unlock()
+	return err
+}
+
+// Dropped returns the number of entries that were discarded because the backlog
+// was full, or because the asynchronous queue of this Log was full.
+func (n *network) Dropped() uint64 {
+	return n.stream.Dropped() + atomic.LoadUint64(&n.c.d)
+}
+func (c *netConn) reconnect(d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	for {
+		select {
+		case <-c.x:
+			return
+		case <-t.C:
+		}
+		// NOTE(dij): Dial without holding the lock, so that entries written while
+		//            connecting can be added to the backlog.
+		x, err := dial(c.n, c.a, c.t)
+		c.m.Lock()
+		if c.s {
+			c.m.Unlock()
+			if x != nil {
+				x.Close()
+			}
+			return
+		}
+		if c.c != nil {
+			// NOTE(dij): 'Sync' connected while we were dialing.
+			if x != nil {
+				x.Close()
+			}
+			c.r = false
+			c.m.Unlock()
+			return
+		}
+		if err == nil {
+			if c.c = x; c.flush() == nil {
+				c.r = false
+				c.m.Unlock()
+				return
+			}
+			c.c.Close()
+			c.c = nil
+		}
+		c.m.Unlock()
+		if d *= 2; d < c.b {
+			d = c.b
+		} else if d > c.w {
+			d = c.w
+		}
+		t.Reset(d)
+	}
+}
+func (c *netConn) flush() error {
+	for len(c.q) > 0 {
+		if err := c.send(c.q[0]); err != nil {
+			return err
+		}
+		c.q[0] = nil
+		c.q = c.q[1:]
+	}
+	c.q = nil
+	return nil
+}
+func (c *netConn) backlog(b []byte) {
+	if c.k <= 0 {
+		atomic.AddUint64(&c.d, 1)
+		return
+	}
+	if len(c.q) >= c.k {
+		c.q[0] = nil
+		c.q = c.q[1:]
+		atomic.AddUint64(&c.d, 1)
+	}
+	c.q = append(c.q, append([]byte(nil), b...))
+}
+func (c *netConn) send(b []byte) error {
+	if c.o > 0 {
+		c.c.SetWriteDeadline(time.Now().Add(c.o))
+	}
+	_, err := c.c.Write(b)
+	return err
+}
+func (n *network) With(v ...Field) Log {
+	if len(v) == 0 {
+		return n
+	}
+	return &network{c: n.c, stream: *n.stream.With(v...).(*stream)}
+}
+func (n *network) Fatal(m string, v ...interface{}) {
+	n.write(Fatal, 0, m, v)
+	if FatalExits {
+		n.Sync()
+		os.Exit(1)
+	}
+}
+func (c *netConn) Write(b []byte) (int, error) {
+	n := len(b)
+	c.m.Lock()
+	if c.s {
+		c.m.Unlock()
+		return 0, os.ErrClosed
+	}
+	if n > 0 && b[n-1] == '\n' && (c.f || !isStream(c.n)) {
+		b = b[:n-1]
+	}
+	if c.f {
+		c.v = append(strconv.AppendInt(c.v[:0], int64(len(b)), 10), ' ')
+		c.v = append(c.v, b...)
+		b = c.v
+	}
+	// NOTE(dij): All connections are made by the reconnect goroutine, so
+	//            logging does not block while connecting or disconnected.
+	if c.c == nil && !c.r {
+		c.r = true
+		go c.reconnect(0)
+	}
+	if c.c == nil {
+		c.backlog(b)
+		c.m.Unlock()
+		return n, nil
+	}
+	err := c.flush()
+	if err == nil {
+		err = c.send(b)
+	}
+	if err != nil {
+		c.backlog(b)
+		c.drop()
+	}
+	c.m.Unlock()
+	return n, nil
+}
@@ -0,0 +1,155 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+type dropper interface {
+	Dropped() uint64
+}
+
+func TestNetworkAlign(t *testing.T) {
+	if o := unsafe.Offsetof(netConn{}.d); o%8 != 0 {
+		t.Fatalf("netConn.d is at offset %d, which is not 8-byte aligned", o)
+	}
+}
+func closedAddr(t *testing.T) string {
+	n, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := n.Addr().String()
+	n.Close()
+	return a
+}
+func readLines(t *testing.T, c net.Conn, n int) []string {
+	var (
+		r = bufio.NewReader(c)
+		v = make([]string, 0, n)
+	)
+	c.SetReadDeadline(time.Now().Add(time.Second * 5))
+	for len(v) < n {
+		s, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		v = append(v, strings.TrimSpace(s))
+	}
+	return v
+}
+func TestNetworkBacklog(t *testing.T) {
+	a := closedAddr(t)
+	l, err := Network("tcp", a, Flags(0), Backlog(2), Backoff(time.Millisecond*10, time.Millisecond*20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.(io.Closer).Close()
+	for _, s := range []string{"1", "2", "3", "4", "5"} {
+		x := time.Now()
+		if l.Warning(s); time.Since(x) > time.Second {
+			t.Fatal("write blocked while disconnected")
+		}
+	}
+	if d := l.(dropper).Dropped(); d != 3 {
+		t.Fatalf("Log dropped %d entries, expected 3", d)
+	}
+	n, err := net.Listen("tcp", a)
+	if err != nil {
+		t.Skipf("cannot listen on %s again: %s", a, err)
+	}
+	defer n.Close()
+	c, err := n.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if v := readLines(t, c, 2); !strings.HasSuffix(v[0], "4") || !strings.HasSuffix(v[1], "5") {
+		t.Fatalf("received %q, expected the newest entries", v)
+	}
+	if d := l.(dropper).Dropped(); d != 3 {
+		t.Fatalf("Log dropped %d entries, expected 3", d)
+	}
+}
+func TestNetworkClose(t *testing.T) {
+	l, err := Network("tcp", closedAddr(t), Backlog(10), Backoff(time.Millisecond*10, time.Millisecond*20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Warning("1")
+	l.Warning("2")
+	l.Warning("3")
+	if d := l.(dropper).Dropped(); d != 0 {
+		t.Fatalf("Log dropped %d entries before Close, expected zero", d)
+	}
+	if err = l.(io.Closer).Close(); err != nil {
+		t.Fatal(err)
+	}
+	if d := l.(dropper).Dropped(); d != 3 {
+		t.Fatalf("Log dropped %d entries after Close, expected 3", d)
+	}
+	if l.Warning("4"); l.(dropper).Dropped() != 3 {
+		t.Fatal("entry written after Close was added to the backlog")
+	}
+}
+func TestNetworkSync(t *testing.T) {
+	n, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer n.Close()
+	r := make(chan string, 1)
+	go func() {
+		// NOTE(dij): Both the reconnect goroutine and 'Sync' may connect, only
+		//            one of the connections is used.
+		for {
+			c, err := n.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer c.Close()
+				if s, err := bufio.NewReader(c).ReadString('\n'); err == nil {
+					r <- strings.TrimSpace(s)
+				}
+			}()
+		}
+	}()
+	l, err := Network("tcp", n.Addr().String(), Flags(0), Backoff(time.Hour, time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.(io.Closer).Close()
+	l.Warning("synced")
+	if err = l.(syncer).Sync(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case s := <-r:
+		if !strings.HasSuffix(s, "synced") {
+			t.Fatalf("received %q", s)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("entry was not received")
+	}
+}
@@ -43,6 +43,9 @@ func (nop) Fatal(_ string, _ ...interface{}) {
 func (nop) Panicf(m string, v ...interface{}) {
 	panic(fmt.Sprintf(m, v...))
 }
+func (nop) With(_ ...Field) Log {
+	return NOP
+}
 
 func (nop) SetLevel(_ Level)                               {}
 func (nop) SetPrefix(_ string)                             {}
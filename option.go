@@ -36,6 +36,24 @@ const (
 	setPrint
 	setAppend
 	setPrefix
+	setFormat
+	setSize
+	setRotate
+	setBackups
+	setCompress
+	setAsync
+	setTLS
+	setFacility
+	setRFC
+	setSocket
+	setBackoff
+	setTimeout
+	setBacklog
+	setBatch
+	setHeader
+	setPayload
+	setColor
+	setAtomic
 )
 
 type setting uint8
@@ -43,6 +61,9 @@ type settingFlags int8
 type settingPrint uint8
 type settingAppend bool
 type settingPrefix string
+type settingFormat struct {
+	Formatter
+}
 
 // Option is an interface that allows for passing a vardict of potential
 // settings that can be used during creation of a logging instance.
@@ -88,3 +109,12 @@ func (settingAppend) setting() setting {
 func (settingPrefix) setting() setting {
 	return setPrefix
 }
+func (settingFormat) setting() setting {
+	return setFormat
+}
+func (s settingFormat) get() Formatter {
+	if s.Formatter == nil {
+		return Text
+	}
+	return s.Formatter
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"os"
+	"sync"
+)
+
+var files struct {
+	e map[*rotator]*file
+	sync.Mutex
+}
+
+// ReopenAll will call 'Reopen' on every open file backed Log. File backed Logs
+// are tracked once created and are no longer tracked once closed.
+//
+// The first error encountered will be returned after all Logs are reopened.
+func ReopenAll() error {
+	files.Lock()
+	var err error
+	for _, f := range files.e {
+		if e := f.Reopen(); e != nil && err == nil {
+			err = e
+		}
+	}
+	files.Unlock()
+	return err
+}
+func track(f *file) {
+	files.Lock()
+	if files.e == nil {
+		files.e = make(map[*rotator]*file)
+	}
+	files.e[f.f] = f
+	files.Unlock()
+}
+func untrack(f *file) {
+	files.Lock()
+	delete(files.e, f.f)
+	files.Unlock()
+}
+
+// Reopen will close and reopen the log file at the same path. This can be used
+// to start a new log file after the current one was moved or removed by an
+// external program, such as logrotate.
+//
+// The new file is opened before the current one is closed and no writes occur
+// during the swap. If the file cannot be opened, the current file will continue
+// to be used and the error will be returned.
+func (f *file) Reopen() error {
+	return f.f.reopen()
+}
+func (r *rotator) reopen() error {
+	if r.m.Lock(); r.f == nil {
+		r.m.Unlock()
+		return os.ErrClosed
+	}
+	f, err := os.OpenFile(r.p, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		r.m.Unlock()
+		return err
+	}
+	r.f.Close()
+	if r.f, r.s = f, 0; r.n > 0 {
+		if i, err := f.Stat(); err == nil {
+			r.s = i.Size()
+		}
+	}
+	r.m.Unlock()
+	return nil
+}
@@ -0,0 +1,28 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build js || plan9
+// +build js plan9
+
+package logx
+
+// ReopenOnHangup will start a goroutine that listens for the SIGHUP signal and
+// calls 'ReopenAll' each time it is received.
+//
+// This platform does not support SIGHUP, so this function does nothing. The
+// returned function can be used to stop listening.
+func ReopenOnHangup() func() {
+	return func() {}
+}
@@ -0,0 +1,279 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Compress is a logging setting that instructs a rotating file backed Log to
+// compress any rotated log files using gzip. Compressed files will have the
+// ".gz" extension added to their names.
+//
+// Compression is done in the background and does not block logging. When used
+// with an HTTP backed Log, this instead instructs the Log to gzip each request
+// body. This setting has no effect on other logging instances or file backed
+// logging instances that do not rotate.
+const Compress = settingCompress(true)
+
+const (
+	// RotateHourly is a logging setting that instructs a file backed Log to rotate
+	// the log file at the start of every hour.
+	//
+	// This setting has no effect on non-file backed logging instances.
+	RotateHourly = settingRotate(time.Hour)
+	// RotateDaily is a logging setting that instructs a file backed Log to rotate
+	// the log file at the start of every day (midnight local time).
+	//
+	// This setting has no effect on non-file backed logging instances.
+	RotateDaily = settingRotate(time.Hour * 24)
+)
+
+const rotateTime = "2006-01-02T15-04-05.000"
+
+type rotator struct {
+	t time.Time
+	f *os.File
+	p string
+	m sync.Mutex
+	c sync.Mutex
+	s int64
+	n int64
+	i time.Duration
+	k int
+	z bool
+}
+type backup struct {
+	t time.Time
+	p string
+	n uint64
+}
+type settingSize int64
+type settingRotate time.Duration
+type settingBackups int
+type settingCompress bool
+
+// MaxSize will create an Option interface that instructs a file backed Log to
+// rotate the log file once it reaches the size 'n' in bytes. The current file
+// is renamed with a timestamp suffix and a new file is created in its place.
+//
+// Values less than or equal to zero disable size based rotation. This setting
+// has no effect on non-file backed logging instances.
+func MaxSize(n int64) Option {
+	return settingSize(n)
+}
+
+// MaxBackups will create an Option interface that instructs a rotating file
+// backed Log to only keep the 'n' newest rotated log files. Older files will be
+// removed after a rotation.
+//
+// Values less than or equal to zero will keep all rotated files. This setting
+// has no effect on non-file backed logging instances.
+func MaxBackups(n int) Option {
+	return settingBackups(n)
+}
+func (settingSize) setting() setting {
+	return setSize
+}
+func (settingRotate) setting() setting {
+	return setRotate
+}
+func (settingBackups) setting() setting {
+	return setBackups
+}
+func (settingCompress) setting() setting {
+	return setCompress
+}
+func (r *rotator) next(t time.Time) {
+	switch {
+	case r.i <= 0:
+		r.t = time.Time{}
+	case r.i >= time.Hour*24:
+		y, m, d := t.Date()
+		r.t = time.Date(y, m, d+1, 0, 0, 0, 0, t.Location())
+	default:
+		r.t = t.Truncate(r.i).Add(r.i)
+	}
+}
+func parseBackup(n string) (time.Time, uint64, bool) {
+	var (
+		c   uint64
+		err error
+	)
+	n = strings.TrimSuffix(n, ".gz")
+	if len(n) > len(rotateTime)+1 && n[len(rotateTime)] == '-' {
+		if c, err = strconv.ParseUint(n[len(rotateTime)+1:], 10, 32); err != nil {
+			return time.Time{}, 0, false
+		}
+		n = n[:len(rotateTime)]
+	}
+	if len(n) != len(rotateTime) {
+		return time.Time{}, 0, false
+	}
+	t, err := time.Parse(rotateTime, n)
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+	return t, c, true
+}
+func backupName(p string, t time.Time) string {
+	// NOTE(dij): Add a counter to the name if a backup with the same timestamp
+	//            exists, so a rename never replaces an older backup.
+	v := p + "." + t.Format(rotateTime)
+	for i, s := 1, v; ; i++ {
+		if _, err := os.Stat(s); err == nil {
+			s = v + "-" + strconv.Itoa(i)
+			continue
+		}
+		if _, err := os.Stat(s + ".gz"); err == nil {
+			s = v + "-" + strconv.Itoa(i)
+			continue
+		}
+		return s
+	}
+}
+func (r *rotator) rotate(t time.Time) error {
+	r.f.Close()
+	var (
+		v   = backupName(r.p, t)
+		n   = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+		err = os.Rename(r.p, v)
+	)
+	if err == nil {
+		n |= os.O_TRUNC
+	}
+	f, err2 := os.OpenFile(r.p, n, 0644)
+	if err2 != nil {
+		// NOTE(dij): Reopen the file that was just closed, so writes keep working
+		//            until the next rotation attempt.
+		o := r.p
+		if err == nil {
+			o = v
+		}
+		if f, err = os.OpenFile(o, os.O_WRONLY|os.O_APPEND, 0644); err == nil {
+			r.f = f
+		}
+		return err2
+	}
+	// NOTE(dij): Reset the rotation state even if the rename failed, so we
+	//            don't attempt to rotate again on every following write.
+	r.f, r.s = f, 0
+	if r.next(t); err != nil {
+		return err
+	}
+	if r.z || r.k > 0 {
+		go r.clean(v)
+	}
+	return nil
+}
+func (r *rotator) clean(v string) {
+	r.c.Lock()
+	if r.z {
+		if err := compress(v); err == nil {
+			os.Remove(v)
+		}
+	}
+	if r.k > 0 {
+		var (
+			b    = filepath.Base(r.p) + "."
+			e, _ = filepath.Glob(filepath.Join(filepath.Dir(r.p), b+"*"))
+			x    = make([]backup, 0, len(e))
+		)
+		for i := range e {
+			if t, n, ok := parseBackup(filepath.Base(e[i])[len(b):]); ok {
+				x = append(x, backup{p: e[i], t: t, n: n})
+			}
+		}
+		// NOTE(dij): Sort by the timestamp and counter, as the names do not sort
+		//            by age when a counter or the ".gz" extension is added.
+		sort.Slice(x, func(i, j int) bool {
+			if !x[i].t.Equal(x[j].t) {
+				return x[i].t.Before(x[j].t)
+			}
+			return x[i].n < x[j].n
+		})
+		if len(x) > r.k {
+			for _, v := range x[:len(x)-r.k] {
+				os.Remove(v.p)
+			}
+		}
+	}
+	r.c.Unlock()
+}
+func compress(s string) error {
+	i, err := os.Open(s)
+	if err != nil {
+		return err
+	}
+	o, err := os.OpenFile(s+".gz", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		i.Close()
+		return err
+	}
+	w := gzip.NewWriter(o)
+	if _, err = io.Copy(w, i); err == nil {
+		err = w.Close()
+	}
+	if i.Close(); err != nil {
+		o.Close()
+		os.Remove(s + ".gz")
+		return err
+	}
+	return o.Close()
+}
+func (r *rotator) Sync() error {
+	if r.m.Lock(); r.f == nil {
+		r.m.Unlock()
+		return os.ErrClosed
+	}
+	err := r.f.Sync()
+	r.m.Unlock()
+	return err
+}
+func (r *rotator) Close() error {
+	if r.m.Lock(); r.f == nil {
+		r.m.Unlock()
+		return nil
+	}
+	err := r.f.Close()
+	r.f = nil
+	r.m.Unlock()
+	return err
+}
+func (r *rotator) Write(b []byte) (int, error) {
+	if r.m.Lock(); r.f == nil {
+		r.m.Unlock()
+		return 0, os.ErrClosed
+	}
+	var err error
+	if (r.n > 0 && r.s > 0 && r.s+int64(len(b)) > r.n) || (!r.t.IsZero() && !time.Now().Before(r.t)) {
+		err = r.rotate(time.Now())
+	}
+	n, err2 := r.f.Write(b)
+	if r.s += int64(n); err2 != nil {
+		err = err2
+	}
+	r.m.Unlock()
+	return n, err
+}
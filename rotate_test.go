@@ -0,0 +1,93 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRotateSameTime(t *testing.T) {
+	var (
+		d = tempDir(t)
+		p = filepath.Join(d, "test.log")
+	)
+	f, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		r = &rotator{f: f, p: p}
+		n = time.Now()
+	)
+	defer r.Close()
+	for _, s := range []string{"first\n", "second\n", "third\n"} {
+		if _, err = r.Write([]byte(s)); err != nil {
+			t.Fatal(err)
+		}
+		if err = r.rotate(n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	e, _ := filepath.Glob(p + ".*")
+	if len(e) != 3 {
+		t.Fatalf("found %d backups, expected 3", len(e))
+	}
+	for i := range e {
+		if _, _, ok := parseBackup(filepath.Base(e[i])[len("test.log."):]); !ok {
+			t.Fatalf("backup %q was not recognized", e[i])
+		}
+	}
+	r.k = 1
+	r.clean("")
+	if e, _ = filepath.Glob(p + ".*"); len(e) != 1 {
+		t.Fatalf("found %d backups after cleaning, expected 1", len(e))
+	}
+	b, err := ioutil.ReadFile(e[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "third\n" {
+		t.Fatalf("newest backup contains %q, expected %q", b, "third\n")
+	}
+}
+func TestRotateCleanOrder(t *testing.T) {
+	var (
+		p = filepath.Join(tempDir(t), "test.log")
+		n = time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC)
+		x = p + "." + n.Format(rotateTime)
+	)
+	for _, s := range []string{
+		p + "." + n.Add(-time.Hour).Format(rotateTime) + ".gz", x + ".gz", x + "-1.gz", x + "-2", x + "-10", p + ".other",
+	} {
+		if err := ioutil.WriteFile(s, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	(&rotator{p: p, k: 2}).clean("")
+	e, _ := filepath.Glob(p + ".*")
+	if len(e) != 3 {
+		t.Fatalf("found %v after cleaning, expected 3 files", e)
+	}
+	for _, s := range []string{x + "-2", x + "-10", p + ".other"} {
+		if _, err := os.Stat(s); err != nil {
+			t.Fatalf("file %q was removed", s)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Router is a type of Log that dispatches each message to the Log instances
+// that were added with a Level range that contains the Level of the message.
+//
+// Unlike Multi, the 'SetLevel' function of a Router does not change the Level
+// of any of its Log instances. Each Log instance keeps its own Level, which is
+// checked after the message is routed to it.
+//
+// Use the 'Routes' function to create a Router.
+type Router struct {
+	r []route
+	l AtomicLevel
+	p AtomicLevel
+}
+type route struct {
+	Log
+	b, t Level
+}
+
+// Routes returns a Router that will write messages with a Level of at least
+// 'Trace' and will use 'Info' for the 'Print*' functions.
+func Routes() *Router {
+	return &Router{l: NewAtomicLevel(Trace), p: NewAtomicLevel(Info)}
+}
+
+// Add appends the Log 'l' to this Router. Messages with a Level between 'min'
+// and 'max' (inclusive) will be written to it. Use 'Panic' as the 'max' value
+// to include all Levels above 'min'.
+//
+// A Log may be added multiple times with different Level ranges.
+func (r *Router) Add(l Log, min, max Level) {
+	if l == nil || min > max {
+		return
+	}
+	r.r = append(r.r, route{Log: l, b: min, t: max})
+}
+
+// SetLevel changes the minimum Level of messages that this Router will write.
+// This does not change the Level of any of the Log instances in this Router.
+func (r *Router) SetLevel(l Level) {
+	r.l.SetLevel(l)
+}
+
+// SetPrefix changes the current logging prefix of each Log instance in this
+// Router.
+func (r *Router) SetPrefix(p string) {
+	for i := range r.r {
+		r.r[i].SetPrefix(p)
+	}
+}
+
+// SetPrintLevel sets the logging level used when 'Print*' statements are called.
+// Messages written by the 'Print*' functions are routed using this Level.
+func (r *Router) SetPrintLevel(n Level) {
+	r.p.SetLevel(n)
+}
+func (r *Router) levels() (Level, Level) {
+	return r.l.Level(), r.p.Level()
+}
+
+// With returns a Router that contains a child Log for each Log instance in this
+// Router, with each child including the supplied Fields. The Level ranges are
+// kept the same.
+func (r *Router) With(f ...Field) Log {
+	n := &Router{l: NewAtomicLevel(r.l.Level()), p: NewAtomicLevel(r.p.Level()), r: make([]route, len(r.r))}
+	for i := range r.r {
+		n.r[i] = route{Log: r.r[i].With(f...), b: r.r[i].b, t: r.r[i].t}
+	}
+	return n
+}
+
+// Sync will call the 'Sync' function on each Log instance in this Router that
+// supports it, such as file backed Logs.
+//
+// The first error encountered will be returned after all Logs are synced.
+func (r *Router) Sync() error {
+	var err error
+	for i := range r.r {
+		if x, ok := r.r[i].Log.(syncer); ok {
+			if e := x.Sync(); e != nil && err == nil {
+				err = e
+			}
+		}
+	}
+	return err
+}
+
+// Close will call the 'Close' function on each Log instance in this Router that
+// supports it, such as file backed Logs.
+//
+// The first error encountered will be returned after all Logs are closed.
+func (r *Router) Close() error {
+	var err error
+	for i := range r.r {
+		if x, ok := r.r[i].Log.(io.Closer); ok {
+			if e := x.Close(); e != nil && err == nil {
+				err = e
+			}
+		}
+	}
+	return err
+}
+
+// Log fulfills the LogWriter interface and writes the message to each Log
+// instance in this Router that has a Level range containing the Level 'l'.
+//
+// Log instances that are not LogWriters will have the matching Log function
+// called instead, with the 'Fatal' and 'Panic' Levels written as 'Error' to
+// prevent exiting before all logs are written.
+func (r *Router) Log(l Level, c int, s string, v ...interface{}) {
+	if l == Print {
+		l = r.p.Level()
+	}
+	if l < r.l.Level() {
+		return
+	}
+	for i := range r.r {
+		if l >= r.r[i].b && l <= r.r[i].t {
+			logAs(r.r[i].Log, l, c+1, s, v)
+		}
+	}
+}
+
+// Print writes a message to the logger.
+//
+// The function arguments are similar to 'fmt.Sprint' and 'fmt.Print'. The only
+// argument is a vardict of interfaces that can be used to output a string value.
+//
+// This function is affected by the setting of 'SetPrintLevel'. By default,
+// this will print as an 'Info' logging message.
+func (r *Router) Print(v ...interface{}) {
+	r.Log(Print, 1, "", v...)
+}
+
+// Panic writes a panic message to the logger.
+//
+// This function will result in the program exiting with a Go 'panic()' after
+// being called. The function arguments are similar to 'fmt.Sprint' and 'fmt.Print.'
+// The only argument is a vardict of interfaces that can be used to output a
+// string value.
+func (r *Router) Panic(v ...interface{}) {
+	r.Log(Panic, 1, "", v...)
+	r.Sync()
+	panic(fmt.Sprint(v...))
+}
+
+// Println writes a message to the logger.
+//
+// The function arguments are similar to fmt.Sprintln and fmt.Println. The only
+// argument is a vardict of interfaces that can be used to output a string value.
+//
+// This function is affected by the setting of 'SetPrintLevel'. By default,
+// this will print as an 'Info' logging message.
+func (r *Router) Println(v ...interface{}) {
+	r.Log(Print, 1, "", v...)
+}
+
+// Panicln writes a panic message to the logger.
+//
+// This function will result in the program exiting with a Go 'panic()' after
+// being called. The function arguments are similar to 'fmt.Sprintln' and
+// 'fmt.Println'. The only argument is a vardict of interfaces that
+// can be used to output a string value.
+func (r *Router) Panicln(v ...interface{}) {
+	r.Log(Panic, 1, "", v...)
+	r.Sync()
+	panic(fmt.Sprint(v...))
+}
+
+// Info writes an informational message to the logger.
+//
+// The function arguments are similar to 'fmt.Sprintf' and 'fmt.Printf'. The
+// first argument is a string that can contain formatting characters. The second
+// argument is a vardict of interfaces that can be omitted or used in the supplied
+// format string.
+func (r *Router) Info(s string, v ...interface{}) {
+	r.Log(Info, 1, s, v...)
+}
+
+// Error writes an error message to the logger.
+//
+// The function arguments are similar to 'fmt.Sprintf' and 'fmt.Printf'. The
+// first argument is a string that can contain formatting characters. The second
+// argument is a vardict of interfaces that can be omitted or used in the supplied
+// format string.
+func (r *Router) Error(s string, v ...interface{}) {
+	r.Log(Error, 1, s, v...)
+}
+
+// Fatal writes a fatal message to the logger.
+//
+// This function will result in the program exiting with a non-zero error code
+// after being called, unless the 'logx.FatalExits' setting is 'false'. The
+// function arguments are similar to 'fmt.Sprintf' and 'fmt.Printf'. The first
+// argument is a string that can contain formatting characters. The second argument
+// is a vardict of interfaces that can be omitted or used in the supplied format
+// string.
+func (r *Router) Fatal(s string, v ...interface{}) {
+	r.Log(Fatal, 1, s, v...)
+	if FatalExits {
+		r.Sync()
+		os.Exit(1)
+	}
+}
+
+// Trace writes a tracing message to the logger.
+//
+// The function arguments are similar to 'fmt.Sprintf' and 'fmt.Printf'. The
+// first argument is a string that can contain formatting characters. The second
+// argument is a vardict of interfaces that can be omitted or used in the supplied
+// format string.
+func (r *Router) Trace(s string, v ...interface{}) {
+	r.Log(Trace, 1, s, v...)
+}
+
+// Debug writes a debugging message to the logger.
+//
+// The function arguments are similar to 'fmt.Sprintf' and 'fmt.Printf'. The
+// first argument is a string that can contain formatting characters. The second
+// argument is a vardict of interfaces that can be omitted or used in the supplied
+// format string.
+func (r *Router) Debug(s string, v ...interface{}) {
+	r.Log(Debug, 1, s, v...)
+}
+
+// Printf writes a message to the logger.
+//
+// The function arguments are similar to 'fmt.Sprintf' and 'fmt.Printf'. The
+// first argument is a string that can contain formatting characters. The second
+// argument is a vardict of interfaces that can be omitted or used in the supplied
+// format string.
+//
+// This function is affected by the setting of 'SetPrintLevel'. By default,
+// this will print as an 'Info' logging message.
+func (r *Router) Printf(s string, v ...interface{}) {
+	r.Log(Print, 1, s, v...)
+}
+
+// Panicf writes a panic message to the logger.
+//
+// This function will result in the program exiting with a Go 'panic()' after
+// being called. The function arguments are similar to 'fmt.Sprintf' and 'fmt.Printf'.
+// The first argument is a string that can contain formatting characters. The
+// second argument is a vardict of interfaces that can be omitted or used in
+// the supplied format string.
+func (r *Router) Panicf(s string, v ...interface{}) {
+	r.Log(Panic, 1, s, v...)
+	r.Sync()
+	panic(fmt.Sprintf(s, v...))
+}
+
+// Warning writes a warning message to the logger.
+//
+// The function arguments are similar to 'fmt.Sprintf' and 'fmt.Printf'. The
+// first argument is a string that can contain formatting characters. The second
+// argument is a vardict of interfaces that can be omitted or used in the supplied
+// format string.
+func (r *Router) Warning(s string, v ...interface{}) {
+	r.Log(Warning, 1, s, v...)
+}
@@ -0,0 +1,241 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// FieldSuppressed is the Field key used in the summary message written by
+// sampled and rate limited Logs, with the value being the number of suppressed
+// messages.
+const FieldSuppressed = "suppressed"
+
+type sampler struct {
+	x Log
+	s *sampling
+}
+type sampling struct {
+	t time.Time
+	u time.Time
+	c map[sampleKey]uint64
+	m sync.Mutex
+	d time.Duration
+	x uint64
+	r float64
+	a float64
+	n uint64
+	k uint64
+}
+type sampleKey struct {
+	m string
+	l Level
+}
+
+// Sample returns a Log that wraps the Log 'l' and limits the amount of repeated
+// messages written to it. For each interval 'd', the first 'n' messages with
+// the same Level and message (format string) are written, then only every 'm'th
+// message after that. If 'm' is less than or equal to zero, all messages after
+// the first 'n' are suppressed until the next interval. If 'd' is less than or
+// equal to zero, an interval of one second is used.
+//
+// Messages with the 'Fatal' and 'Panic' Levels are never suppressed. The number
+// of suppressed messages is written as a 'Warning' summary message (with the
+// 'FieldSuppressed' Field) with the first message after each interval, or when
+// 'Sync' or 'Close' are called.
+func Sample(l Log, d time.Duration, n, m int) Log {
+	if n < 0 {
+		n = 0
+	}
+	if m < 0 {
+		m = 0
+	}
+	if d <= 0 {
+		d = time.Second
+	}
+	return &sampler{x: l, s: &sampling{d: d, n: uint64(n), k: uint64(m), c: make(map[sampleKey]uint64)}}
+}
+
+// RateLimit returns a Log that wraps the Log 'l' and limits the amount of messages
+// written to it using a token bucket. The bucket holds 'n' tokens and is refilled
+// at the rate of 'n' tokens for each interval 'd'. Each message written uses a
+// token and messages written when the bucket is empty are suppressed.
+//
+// Messages with the 'Fatal' and 'Panic' Levels are never suppressed. The number
+// of suppressed messages is written as a 'Warning' summary message (with the
+// 'FieldSuppressed' Field) with the first message after each interval, or when
+// 'Sync' or 'Close' are called.
+func RateLimit(l Log, n int, d time.Duration) Log {
+	if n < 1 {
+		n = 1
+	}
+	if d <= 0 {
+		d = time.Second
+	}
+	return &sampler{x: l, s: &sampling{d: d, n: uint64(n), a: float64(n), r: float64(n) / float64(d), u: time.Now()}}
+}
+func (s *sampling) take() uint64 {
+	s.m.Lock()
+	n := s.x
+	s.x = 0
+	s.m.Unlock()
+	return n
+}
+func (s *sampler) SetLevel(l Level) {
+	s.x.SetLevel(l)
+}
+func (s *sampler) SetPrefix(p string) {
+	s.x.SetPrefix(p)
+}
+func (s *sampler) SetPrintLevel(n Level) {
+	s.x.SetPrintLevel(n)
+}
+func (s *sampler) levels() (Level, Level) {
+	return levelsOf(s.x)
+}
+func (s *sampler) With(f ...Field) Log {
+	if len(f) == 0 {
+		return s
+	}
+	return &sampler{x: s.x.With(f...), s: s.s}
+}
+
+// Sync will write the summary message if any messages were suppressed, then
+// will call the 'Sync' function on the wrapped Log if it supports it.
+func (s *sampler) Sync() error {
+	s.summary(s.s.take(), 1)
+	if x, ok := s.x.(syncer); ok {
+		return x.Sync()
+	}
+	return nil
+}
+
+// Close will write the summary message if any messages were suppressed, then
+// will call the 'Close' function on the wrapped Log if it supports it.
+func (s *sampler) Close() error {
+	s.summary(s.s.take(), 1)
+	if x, ok := s.x.(io.Closer); ok {
+		return x.Close()
+	}
+	return nil
+}
+func (s *sampler) summary(n uint64, c int) {
+	if n == 0 {
+		return
+	}
+	logAs(s.x.With(F(FieldSuppressed, n)), Warning, c+1, "%d messages were suppressed", []interface{}{n})
+}
+func (s *sampling) allow(l Level, m string, v []interface{}) (uint64, bool) {
+	var (
+		t  = time.Now()
+		ok bool
+		n  uint64
+	)
+	s.m.Lock()
+	if t.Sub(s.t) >= s.d {
+		if n, s.x, s.t = s.x, 0, t; s.c != nil && len(s.c) > 0 {
+			s.c = make(map[sampleKey]uint64, len(s.c))
+		}
+	}
+	if s.c != nil {
+		if len(m) == 0 {
+			m = message(m, v)
+		}
+		k := sampleKey{m: m, l: l}
+		i := s.c[k] + 1
+		s.c[k] = i
+		ok = i <= s.n || (s.k > 0 && (i-s.n)%s.k == 0)
+	} else {
+		if s.a += float64(t.Sub(s.u)) * s.r; s.a > float64(s.n) {
+			s.a = float64(s.n)
+		}
+		if s.u = t; s.a >= 1 {
+			s.a--
+			ok = true
+		}
+	}
+	if !ok {
+		s.x++
+	}
+	s.m.Unlock()
+	return n, ok
+}
+func (s *sampler) Log(l Level, c int, m string, v ...interface{}) {
+	if l == Fatal || l == Panic {
+		logAs(s.x, l, c+1, m, v)
+		return
+	}
+	// NOTE(dij): Skip messages the wrapped Log would drop, so they don't use
+	//            up the limits or get counted as suppressed.
+	if !enabled(s.x, l) {
+		return
+	}
+	n, ok := s.s.allow(l, m, v)
+	s.summary(n, c+1)
+	if ok {
+		logAs(s.x, l, c+1, m, v)
+	}
+}
+func (s *sampler) Print(v ...interface{}) {
+	s.Log(Print, 1, "", v...)
+}
+func (s *sampler) Panic(v ...interface{}) {
+	s.Log(Panic, 1, "", v...)
+	s.Sync()
+	panic(fmt.Sprint(v...))
+}
+func (s *sampler) Println(v ...interface{}) {
+	s.Log(Print, 1, "", v...)
+}
+func (s *sampler) Panicln(v ...interface{}) {
+	s.Log(Panic, 1, "", v...)
+	s.Sync()
+	panic(fmt.Sprint(v...))
+}
+func (s *sampler) Info(m string, v ...interface{}) {
+	s.Log(Info, 1, m, v...)
+}
+func (s *sampler) Error(m string, v ...interface{}) {
+	s.Log(Error, 1, m, v...)
+}
+func (s *sampler) Fatal(m string, v ...interface{}) {
+	s.Log(Fatal, 1, m, v...)
+	if FatalExits {
+		s.Sync()
+		os.Exit(1)
+	}
+}
+func (s *sampler) Trace(m string, v ...interface{}) {
+	s.Log(Trace, 1, m, v...)
+}
+func (s *sampler) Debug(m string, v ...interface{}) {
+	s.Log(Debug, 1, m, v...)
+}
+func (s *sampler) Printf(m string, v ...interface{}) {
+	s.Log(Print, 1, m, v...)
+}
+func (s *sampler) Panicf(m string, v ...interface{}) {
+	s.Log(Panic, 1, m, v...)
+	s.Sync()
+	panic(fmt.Sprintf(m, v...))
+}
+func (s *sampler) Warning(m string, v ...interface{}) {
+	s.Log(Warning, 1, m, v...)
+}
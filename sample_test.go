@@ -0,0 +1,59 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestRateLimitFiltered(t *testing.T) {
+	var b bytes.Buffer
+	l := RateLimit(Writer(&b, Warning, Flags(0)), 1, time.Hour)
+	l.Debug("noise")
+	l.Warning("real warning")
+	if err := l.(*sampler).Sync(); err != nil {
+		t.Fatalf("Sync returned an error: %s", err)
+	}
+	if s := b.String(); s != "[ WARN]: real warning\n" {
+		t.Fatalf("unexpected output %q", s)
+	}
+}
+func TestSampleFiltered(t *testing.T) {
+	var b bytes.Buffer
+	l := Sample(Writer(&b, Info, Flags(0), PrintLevel(Debug)), time.Hour, 1, 0)
+	for i := 0; i < 3; i++ {
+		l.Debug("noise")
+		l.Print("print")
+	}
+	l.Info("shown")
+	l.Info("shown")
+	l.(*sampler).Sync()
+	if s := b.String(); s != "[ INFO]: shown\n[ WARN] suppressed=1: 1 messages were suppressed\n" {
+		t.Fatalf("unexpected output %q", s)
+	}
+}
+func TestSampleInterval(t *testing.T) {
+	var b bytes.Buffer
+	l := Sample(Writer(&b, Info, Flags(0)), 0, 1, 0)
+	for i := 0; i < 5; i++ {
+		l.Info("repeated")
+	}
+	if n := bytes.Count(b.Bytes(), []byte("repeated")); n != 1 {
+		t.Fatalf("%d of 5 messages were written, expected 1", n)
+	}
+}
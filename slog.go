@@ -0,0 +1,272 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.21
+// +build go1.21
+
+package logx
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+)
+
+type handler struct {
+	l Log
+	g string
+}
+type slogger struct {
+	s *slog.Logger
+	p string
+	m sync.Mutex
+	l Level
+	k Level
+}
+
+// Handler returns a 'slog.Handler' that writes all Records to the supplied Log.
+// This allows for a 'slog.Logger' to use any Log as its output.
+//
+// The slog levels are mapped to the closest Level, with anything below
+// 'slog.LevelDebug' becoming 'Trace' and anything above 'slog.LevelError'
+// becoming 'Fatal' or 'Panic'. Attributes are converted to Fields, with any
+// group names prepended to the keys using a '.'.
+//
+// The Log will only write the entry and will not exit or panic on the Fatal or
+// Panic Levels. If the Log is a LogWriter, the caller information of the slog
+// Record will be preserved. Records below the Level of the Log are skipped by
+// the 'slog.Logger' before they are created.
+func Handler(l Log) slog.Handler {
+	if l == nil {
+		l = NOP
+	}
+	return &handler{l: l}
+}
+
+// Slog returns a Log that forwards all entries to the supplied 'slog.Logger'.
+// This allows for a 'slog.Logger' to be used in place of any Log.
+//
+// Each Level is mapped to the matching slog level, with 'Trace' mapped to
+// 'slog.LevelDebug - 4', 'Fatal' mapped to 'slog.LevelError + 4' and 'Panic'
+// mapped to 'slog.LevelError + 8'. Fields are converted to slog attributes and
+// any prefix is added as the "prefix" attribute.
+//
+// The returned Log keeps its own Level and print Level, which default to
+// 'Trace' and 'Info' respectively, so filtering is left to the 'slog.Logger'.
+func Slog(s *slog.Logger) Log {
+	if s == nil {
+		s = slog.Default()
+	}
+	return &slogger{s: s, l: Trace, k: Info}
+}
+func slogLevel(l Level) slog.Level {
+	switch l {
+	case Trace:
+		return slog.LevelDebug - 4
+	case Debug:
+		return slog.LevelDebug
+	case Info:
+		return slog.LevelInfo
+	case Warning:
+		return slog.LevelWarn
+	case Error:
+		return slog.LevelError
+	case Fatal:
+		return slog.LevelError + 4
+	}
+	return slog.LevelError + 8
+}
+func levelSlog(l slog.Level) Level {
+	switch {
+	case l < slog.LevelDebug:
+		return Trace
+	case l < slog.LevelInfo:
+		return Debug
+	case l < slog.LevelWarn:
+		return Info
+	case l < slog.LevelError:
+		return Warning
+	case l < slog.LevelError+4:
+		return Error
+	case l < slog.LevelError+8:
+		return Fatal
+	}
+	return Panic
+}
+func (s *slogger) SetLevel(l Level) {
+	s.m.Lock()
+	s.l = l
+	s.m.Unlock()
+}
+func (s *slogger) SetPrefix(p string) {
+	s.m.Lock()
+	s.p = p
+	s.m.Unlock()
+}
+func (s *slogger) SetPrintLevel(l Level) {
+	s.m.Lock()
+	s.k = l
+	s.m.Unlock()
+}
+func (s *slogger) Print(v ...interface{}) {
+	s.Log(Print, 1, "", v...)
+}
+func (s *slogger) Panic(v ...interface{}) {
+	s.Log(Panic, 1, "", v...)
+	panic(fmt.Sprint(v...))
+}
+func (s *slogger) Println(v ...interface{}) {
+	s.Log(Print, 1, "", v...)
+}
+func (s *slogger) Panicln(v ...interface{}) {
+	s.Log(Panic, 1, "", v...)
+	panic(fmt.Sprintln(v...))
+}
+func (s *slogger) levels() (Level, Level) {
+	s.m.Lock()
+	l, k := s.l, s.k
+	s.m.Unlock()
+	return l, k
+}
+func (s *slogger) With(f ...Field) Log {
+	if len(f) == 0 {
+		return s
+	}
+	a := make([]interface{}, len(f))
+	for i := range f {
+		a[i] = slog.Any(f[i].Key, f[i].Value)
+	}
+	s.m.Lock()
+	n := &slogger{s: s.s.With(a...), p: s.p, l: s.l, k: s.k}
+	s.m.Unlock()
+	return n
+}
+func (s *slogger) Info(m string, v ...interface{}) {
+	s.Log(Info, 1, m, v...)
+}
+func (s *slogger) Error(m string, v ...interface{}) {
+	s.Log(Error, 1, m, v...)
+}
+func (s *slogger) Fatal(m string, v ...interface{}) {
+	if s.Log(Fatal, 1, m, v...); FatalExits {
+		os.Exit(1)
+	}
+}
+func (s *slogger) Trace(m string, v ...interface{}) {
+	s.Log(Trace, 1, m, v...)
+}
+func (s *slogger) Debug(m string, v ...interface{}) {
+	s.Log(Debug, 1, m, v...)
+}
+func (s *slogger) Printf(m string, v ...interface{}) {
+	s.Log(Print, 1, m, v...)
+}
+func (s *slogger) Panicf(m string, v ...interface{}) {
+	s.Log(Panic, 1, m, v...)
+	panic(fmt.Sprintf(m, v...))
+}
+func (s *slogger) Warning(m string, v ...interface{}) {
+	s.Log(Warning, 1, m, v...)
+}
+func (h *handler) WithGroup(n string) slog.Handler {
+	if len(n) == 0 {
+		return h
+	}
+	return &handler{l: h.l, g: h.g + n + "."}
+}
+func (h *handler) Enabled(_ context.Context, l slog.Level) bool {
+	return enabled(h.l, levelSlog(l))
+}
+func (h *handler) WithAttrs(a []slog.Attr) slog.Handler {
+	if len(a) == 0 {
+		return h
+	}
+	f := make([]Field, 0, len(a))
+	for i := range a {
+		f = attrFields(f, h.g, a[i])
+	}
+	return &handler{l: h.l.With(f...), g: h.g}
+}
+func (s *slogger) Log(l Level, c int, m string, v ...interface{}) {
+	s.m.Lock()
+	if l == Print {
+		l = s.k
+	}
+	if s.l > l {
+		s.m.Unlock()
+		return
+	}
+	p := s.p
+	s.m.Unlock()
+	var (
+		x = context.Background()
+		e = slogLevel(l)
+	)
+	if !s.s.Enabled(x, e) {
+		return
+	}
+	var (
+		k [1]uintptr
+		r slog.Record
+	)
+	runtime.Callers(2+c, k[:])
+	if r = slog.NewRecord(time.Now(), e, message(m, v), k[0]); len(p) > 0 {
+		r.AddAttrs(slog.String("prefix", p))
+	}
+	s.s.Handler().Handle(x, r)
+}
+func attrFields(f []Field, g string, a slog.Attr) []Field {
+	if a.Value = a.Value.Resolve(); a.Equal(slog.Attr{}) {
+		return f
+	}
+	if a.Value.Kind() != slog.KindGroup {
+		return append(f, Field{Key: g + a.Key, Value: a.Value.Any()})
+	}
+	if len(a.Key) > 0 {
+		g = g + a.Key + "."
+	}
+	for _, v := range a.Value.Group() {
+		f = attrFields(f, g, v)
+	}
+	return f
+}
+func (h *handler) Handle(_ context.Context, r slog.Record) error {
+	l := h.l
+	if r.NumAttrs() > 0 {
+		f := make([]Field, 0, r.NumAttrs())
+		r.Attrs(func(a slog.Attr) bool {
+			f = attrFields(f, h.g, a)
+			return true
+		})
+		l = l.With(f...)
+	}
+	v := levelSlog(r.Level)
+	c := 0
+	if r.PC != 0 {
+		var k [64]uintptr
+		for i, n := 0, runtime.Callers(1, k[:]); i < n; i++ {
+			if k[i] == r.PC {
+				c = i
+				break
+			}
+		}
+	}
+	logAs(l, v, c, "", []interface{}{r.Message})
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.21
+// +build go1.21
+
+package logx
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+)
+
+func TestHandlerEnabled(t *testing.T) {
+	var (
+		b bytes.Buffer
+		l = Writer(&b, Info)
+		h = Handler(l)
+	)
+	if h.Enabled(context.Background(), slog.LevelDebug) {
+		t.Fatal("Debug is enabled for an Info Log")
+	}
+	if !h.Enabled(context.Background(), slog.LevelWarn) {
+		t.Fatal("Warning is not enabled for an Info Log")
+	}
+	s := slog.New(h)
+	s.Debug("skipped")
+	if b.Len() > 0 {
+		t.Fatalf("Debug record was written: %q", b.String())
+	}
+	l.SetLevel(Debug)
+	if s.Debug("written"); !bytes.Contains(b.Bytes(), []byte("written")) {
+		t.Fatal("Debug record was not written after changing the Level")
+	}
+	if !Handler(NOP).Enabled(context.Background(), slog.LevelDebug) {
+		t.Fatal("Log with an unknown Level is not enabled")
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"log"
+	"runtime"
+	"strings"
+)
+
+type stdWriter struct {
+	l Log
+	g *log.Logger
+	v Level
+}
+
+// StdLogger returns a standard library '*log.Logger' that writes each message
+// to the supplied Log using the Level 'v'. This can be used for any functions
+// or structs that require a '*log.Logger', such as 'http.Server.ErrorLog'.
+//
+// The returned Logger has no flags or prefix set, as the Log will add its own.
+// If flags are set on the returned Logger, the parts of the standard header that
+// they add are removed before the message is written to the Log. Any prefix set
+// on the returned Logger is kept. The Level 'Print' may be used to use the print
+// Level of the Log.
+//
+// If the Log is a LogWriter, the caller information will point to the function
+// that called the '*log.Logger'.
+func StdLogger(l Log, v Level) *log.Logger {
+	if l == nil {
+		l = NOP
+	}
+	w := &stdWriter{l: l, v: v}
+	w.g = log.New(w, "", 0)
+	return w.g
+}
+func skipDigits(s string, n int) (string, bool) {
+	if len(s) < n {
+		return s, false
+	}
+	for i := 0; i < n; i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return s, false
+		}
+	}
+	return s[n:], true
+}
+func stripHeader(s string, f int) string {
+	// NOTE(dij): Date is "YYYY/MM/DD ", time is "HH:MM:SS[.UUUUUU] " and the
+	//            file is "name.go:N: ", each of which is only removed if the
+	//            flags say it's there.
+	if f&log.Ldate != 0 {
+		if v, ok := skipDigits(s, 4); ok && len(v) > 6 && v[0] == '/' && v[3] == '/' && v[6] == ' ' {
+			if _, ok = skipDigits(v[1:], 2); ok {
+				if _, ok = skipDigits(v[4:], 2); ok {
+					s = v[7:]
+				}
+			}
+		}
+	}
+	if f&(log.Ltime|log.Lmicroseconds) != 0 {
+		if v, ok := skipDigits(s, 2); ok && len(v) > 5 && v[0] == ':' && v[3] == ':' {
+			if v, ok = skipDigits(v[4:], 2); ok {
+				if f&log.Lmicroseconds != 0 && len(v) > 7 && v[0] == '.' {
+					if x, ok := skipDigits(v[1:], 6); ok {
+						v = x
+					}
+				}
+				if len(v) > 0 && v[0] == ' ' {
+					s = v[1:]
+				}
+			}
+		}
+	}
+	if f&(log.Lshortfile|log.Llongfile) != 0 {
+		for i := strings.Index(s, ": "); i > 0; {
+			n := i
+			for n > 0 && s[n-1] >= '0' && s[n-1] <= '9' {
+				n--
+			}
+			if n < i && n > 1 && s[n-1] == ':' {
+				return s[i+2:]
+			}
+			x := strings.Index(s[i+2:], ": ")
+			if x == -1 {
+				break
+			}
+			i += x + 2
+		}
+	}
+	return s
+}
+func (w *stdWriter) Write(b []byte) (int, error) {
+	s, x := string(b), w.g.Flags()
+	if p := w.g.Prefix(); x&log.Lmsgprefix == 0 && len(p) > 0 && strings.HasPrefix(s, p) {
+		s = p + stripHeader(s[len(p):], x)
+	} else {
+		s = stripHeader(s, x)
+	}
+	if len(s) > 0 && s[len(s)-1] == '\n' {
+		s = s[:len(s)-1]
+	}
+	var (
+		k [32]uintptr
+		c = 1
+		f = runtime.CallersFrames(k[:runtime.Callers(2, k[:])])
+	)
+	for {
+		v, more := f.Next()
+		if !strings.HasPrefix(v.Function, "log.") {
+			break
+		}
+		if c++; !more {
+			break
+		}
+	}
+	logAs(w.l, w.v, c, "", []interface{}{s})
+	return len(b), nil
+}
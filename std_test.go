@@ -0,0 +1,55 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"bytes"
+	"log"
+	"testing"
+)
+
+func TestStripHeader(t *testing.T) {
+	for i, v := range []struct {
+		s, e string
+		f    int
+	}{
+		{"main.go:10: bad request", "main.go:10: bad request", 0},
+		{"2023/01/02 main.go:10: bad request", "2023/01/02 main.go:10: bad request", 0},
+		{"2023/01/02 hello", "hello", log.Ldate},
+		{"2023/01/02 15:04:05 hello", "hello", log.LstdFlags},
+		{"2023/01/02 15:04:05.123456 hello", "hello", log.Ldate | log.Lmicroseconds},
+		{"15:04:05 main.go:10: bad request", "main.go:10: bad request", log.Ltime},
+		{"std.go:10: main.go:10: bad request", "main.go:10: bad request", log.Lshortfile},
+		{"/src/my dir/std.go:10: bad request", "bad request", log.Llongfile},
+		{"2023/01/02 15:04:05 std.go:1: a: b", "a: b", log.LstdFlags | log.Lshortfile},
+		{"not a header", "not a header", log.LstdFlags | log.Lshortfile},
+	} {
+		if s := stripHeader(v.s, v.f); s != v.e {
+			t.Errorf("case %d: stripHeader returned %q, expected %q", i, s, v.e)
+		}
+	}
+}
+func TestStdLogger(t *testing.T) {
+	var b bytes.Buffer
+	g := StdLogger(Writer(&b, Info, Flags(0)), Warning)
+	g.Print("main.go:10: bad request")
+	g.SetFlags(log.LstdFlags | log.Lshortfile)
+	g.SetPrefix("http: ")
+	g.Print("failed")
+	if s := b.String(); s != "[ WARN]: main.go:10: bad request\n[ WARN]: http: failed\n" {
+		t.Fatalf("unexpected output %q", s)
+	}
+}
@@ -20,6 +20,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sync/atomic"
+	"time"
 )
 
 // DefaultConsole is a pointer to the output that all the console Log structs
@@ -30,12 +32,14 @@ var DefaultConsole io.Writer = os.Stderr
 
 type file struct {
 	stream
-	f string
+	f *rotator
 }
 type stream struct {
 	*logger
-	l Level
-	p Level
+	x []Field
+	l AtomicLevel
+	p AtomicLevel
+	s bool
 }
 
 // Console returns a console logger that uses the Console writer.
@@ -43,51 +47,83 @@ func Console(o ...Option) Log {
 	return Writer(DefaultConsole, o...)
 }
 func (s *stream) SetLevel(n Level) {
-	s.l = n
+	s.l.SetLevel(n)
 }
 func (s *stream) SetPrefix(p string) {
 	s.logger.SetPrefix(p)
 }
 func (s *stream) SetPrintLevel(n Level) {
-	s.p = n
+	s.p.SetLevel(n)
+}
+func (s *stream) levels() (Level, Level) {
+	return s.l.Level(), s.p.Level()
+}
+func (s *stream) With(f ...Field) Log {
+	if s == nil {
+		return Global.With(f...)
+	}
+	if len(f) == 0 {
+		return s
+	}
+	// NOTE(dij): Children get their own Levels, unless the Level is a shared
+	//            AtomicLevel handle.
+	n := &stream{l: s.l, s: s.s, p: NewAtomicLevel(s.p.Level()), logger: s.logger, x: make([]Field, 0, len(s.x)+len(f))}
+	if !s.s {
+		n.l = NewAtomicLevel(s.l.Level())
+	}
+	n.x = append(append(n.x, s.x...), f...)
+	return n
 }
 func (s *stream) Print(v ...interface{}) {
 	if s == nil {
-		Global.(LogWriter).Log(Print, 0, "", v...)
+		logGlobal(Print, "", v)
 		return
 	}
-	s.Log(s.p, 0, "", v...)
+	s.write(s.p.Level(), 0, "", v)
 }
 func (s *stream) Panic(v ...interface{}) {
 	if s == nil {
-		Global.(LogWriter).Log(Panic, 0, "", v...)
+		logGlobal(Panic, "", v)
 	} else {
-		s.Log(Panic, 0, "", v...)
+		s.write(Panic, 0, "", v)
+		s.flush()
 	}
 	panic(fmt.Sprintln(v...))
 }
 func (s *stream) Println(v ...interface{}) {
 	if s == nil {
-		Global.(LogWriter).Log(Print, 0, "", v...)
+		logGlobal(Print, "", v)
 		return
 	}
-	s.Log(s.p, 0, "", v...)
+	s.write(s.p.Level(), 0, "", v)
 }
 func (s *stream) Panicln(v ...interface{}) {
 	if s == nil {
-		Global.(LogWriter).Log(Panic, 0, "", v...)
+		logGlobal(Panic, "", v)
 	} else {
-		s.Log(Panic, 0, "", v...)
+		s.write(Panic, 0, "", v)
+		s.flush()
 	}
 	panic(fmt.Sprintln(v...))
 }
 
 // Writer returns a Log instance based on the Writer 'w' for the logging output
 // and allows specifying non-default Logging options.
+//
+// When using the 'Text' Encoding, the "[LEVEL]" tag of each entry is colored if
+// 'w' is a terminal. The "NO_COLOR" and "FORCE_COLOR" environment variables and
+// the 'NoColor', 'ForceColor' and 'ColorLine' options can be used to change this.
+//
+// Calls that are below the Level of the returned Log do not allocate, except as
+// described in the documentation of the Log interface.
 func Writer(w io.Writer, o ...Option) Log {
 	var (
 		f    settingFlags = -1
 		p    settingPrefix
+		e    settingFormat
+		q    settingAsync
+		c    settingColor
+		a    AtomicLevel
 		l, k = invalidLevel, invalidLevel
 	)
 	for i := range o {
@@ -97,12 +133,22 @@ func Writer(w io.Writer, o ...Option) Log {
 		switch o[i].setting() {
 		case setLevel:
 			l, _ = o[i].(Level)
+		case setAtomic:
+			a, _ = o[i].(AtomicLevel)
 		case setFlags:
 			f, _ = o[i].(settingFlags)
 		case setPrint:
-			k, _ = o[i].(Level)
+			v, _ := o[i].(settingPrint)
+			k = Level(v)
 		case setPrefix:
 			p, _ = o[i].(settingPrefix)
+		case setFormat:
+			e, _ = o[i].(settingFormat)
+		case setAsync:
+			q, _ = o[i].(settingAsync)
+		case setColor:
+			v, _ := o[i].(settingColor)
+			c |= v
 		}
 	}
 	if f == -1 {
@@ -114,7 +160,17 @@ func Writer(w io.Writer, o ...Option) Log {
 	if k == invalidLevel {
 		k = Info
 	}
-	return &stream{l: l, p: k, logger: &logger{w: w, p: []byte(p), f: uint8(f)}}
+	if (e.Formatter == nil || e.Formatter == Text) && colored(w, c) {
+		e.Formatter = colorText(c)
+	}
+	v := &stream{l: a, s: a.v != nil, p: NewAtomicLevel(k), logger: &logger{w: w, p: string(p), f: uint8(f), e: e.get()}}
+	if !v.s {
+		v.l = NewAtomicLevel(l)
+	}
+	if q.n > 0 {
+		v.q = newQueue(w, q)
+	}
+	return v
 }
 
 // File will attempt to create a File backed Log instance that will write to file
@@ -122,12 +178,22 @@ func Writer(w io.Writer, o ...Option) Log {
 //
 // This function will truncate the file before starting a new Log if the 'Append'
 // option isn't specified.
+//
+// The 'MaxSize', 'RotateHourly', 'RotateDaily', 'MaxBackups' and 'Compress'
+// options may be used to enable rotation of the log file.
 func File(s string, o ...Option) (Log, error) {
 	var (
 		f    settingFlags = -1
 		p    settingPrefix
+		e    settingFormat
+		q    settingAsync
 		a    settingAppend
+		z    settingCompress
+		r    settingRotate
+		b    settingBackups
+		m    settingSize
 		n    = os.O_WRONLY | os.O_CREATE
+		h    AtomicLevel
 		l, k = invalidLevel, invalidLevel
 	)
 	for i := range o {
@@ -137,14 +203,29 @@ func File(s string, o ...Option) (Log, error) {
 		switch o[i].setting() {
 		case setLevel:
 			l, _ = o[i].(Level)
+		case setAtomic:
+			h, _ = o[i].(AtomicLevel)
 		case setFlags:
 			f, _ = o[i].(settingFlags)
 		case setPrint:
-			k, _ = o[i].(Level)
+			v, _ := o[i].(settingPrint)
+			k = Level(v)
 		case setAppend:
 			a, _ = o[i].(settingAppend)
 		case setPrefix:
 			p, _ = o[i].(settingPrefix)
+		case setFormat:
+			e, _ = o[i].(settingFormat)
+		case setAsync:
+			q, _ = o[i].(settingAsync)
+		case setSize:
+			m, _ = o[i].(settingSize)
+		case setRotate:
+			r, _ = o[i].(settingRotate)
+		case setBackups:
+			b, _ = o[i].(settingBackups)
+		case setCompress:
+			z, _ = o[i].(settingCompress)
 		}
 	}
 	if f == -1 {
@@ -163,87 +244,153 @@ func File(s string, o ...Option) (Log, error) {
 	if err != nil {
 		return nil, errors.New(`cannot open "` + s + `" for logging: ` + err.Error())
 	}
-	return &file{f: s, stream: stream{l: l, p: k, logger: &logger{w: w, p: []byte(p), f: uint8(f)}}}, nil
+	x := &rotator{f: w, p: s, n: int64(m), i: time.Duration(r), k: int(b), z: bool(z)}
+	if a {
+		if i, err := w.Stat(); err == nil {
+			x.s = i.Size()
+		}
+	}
+	x.next(time.Now())
+	v := &file{f: x, stream: stream{l: h, s: h.v != nil, p: NewAtomicLevel(k), logger: &logger{w: x, p: string(p), f: uint8(f), e: e.get()}}}
+	if !v.s {
+		v.l = NewAtomicLevel(l)
+	}
+	if q.n > 0 {
+		v.q = newQueue(x, q)
+	}
+	track(v)
+	return v, nil
+}
+
+// Sync will commit the current contents of the log file to stable storage. If
+// this Log is asynchronous, this will wait for all queued entries to be written
+// first.
+func (f *file) Sync() error {
+	f.flush()
+	return f.f.Sync()
+}
+
+// Close will close the log file. If this Log is asynchronous, this will wait
+// for all queued entries to be written first. Any Log functions called after
+// this will not write any output.
+func (f *file) Close() error {
+	untrack(f)
+	f.close()
+	return f.f.Close()
+}
+func (f *file) With(v ...Field) Log {
+	if len(v) == 0 {
+		return f
+	}
+	return &file{f: f.f, stream: *f.stream.With(v...).(*stream)}
+}
+func (f *file) Fatal(m string, v ...interface{}) {
+	f.write(Fatal, 0, m, v)
+	if FatalExits {
+		f.Sync()
+		os.Exit(1)
+	}
+}
+
+// Sync will wait for all queued entries to be written if this Log is asynchronous.
+// This function does nothing for non-asynchronous Logs.
+func (s *stream) Sync() error {
+	s.flush()
+	return nil
+}
+
+// Close will wait for all queued entries to be written and stop the background
+// writer if this Log is asynchronous. Any Log functions called after this will
+// not write any output. The underlying Writer is not closed.
+//
+// This function does nothing for non-asynchronous Logs.
+func (s *stream) Close() error {
+	s.close()
+	return nil
+}
+
+// Dropped returns the number of entries that were discarded due to the Overflow
+// policy of this Log. This will always return zero if this Log is not asynchronous
+// or uses the 'Block' policy.
+func (s *stream) Dropped() uint64 {
+	if s.q == nil {
+		return 0
+	}
+	return atomic.LoadUint64(&s.q.d)
 }
 func (s *stream) Info(m string, v ...interface{}) {
 	if s == nil {
-		Global.(LogWriter).Log(Info, 0, m, v...)
+		logGlobal(Info, m, v)
 		return
 	}
-	s.Log(Info, 0, m, v...)
+	s.write(Info, 0, m, v)
 }
 func (s *stream) Error(m string, v ...interface{}) {
 	if s == nil {
-		Global.(LogWriter).Log(Error, 0, m, v...)
+		logGlobal(Error, m, v)
 		return
 	}
-	s.Log(Error, 0, m, v...)
+	s.write(Error, 0, m, v)
 }
 func (s *stream) Fatal(m string, v ...interface{}) {
 	if s == nil {
-		Global.(LogWriter).Log(Fatal, 0, m, v...)
+		logGlobal(Fatal, m, v)
 	} else {
-		s.Log(Fatal, 0, m, v...)
+		s.write(Fatal, 0, m, v)
 	}
 	if FatalExits {
+		if s != nil {
+			s.flush()
+		}
 		os.Exit(1)
 	}
 }
 func (s *stream) Trace(m string, v ...interface{}) {
 	if s == nil {
-		Global.(LogWriter).Log(Trace, 0, m, v...)
+		logGlobal(Trace, m, v)
 		return
 	}
-	s.Log(Trace, 0, m, v...)
+	s.write(Trace, 0, m, v)
 }
 func (s *stream) Debug(m string, v ...interface{}) {
 	if s == nil {
-		Global.(LogWriter).Log(Debug, 0, m, v...)
+		logGlobal(Debug, m, v)
 		return
 	}
-	s.Log(Debug, 0, m, v...)
+	s.write(Debug, 0, m, v)
 }
 func (s *stream) Printf(m string, v ...interface{}) {
 	if s == nil {
-		Global.(LogWriter).Log(Print, 0, m, v...)
+		logGlobal(Print, m, v)
 		return
 	}
-	s.Log(s.p, 0, m, v...)
+	s.write(s.p.Level(), 0, m, v)
 }
 func (s *stream) Panicf(m string, v ...interface{}) {
 	if s == nil {
-		Global.(LogWriter).Log(Panic, 0, m, v...)
+		logGlobal(Panic, m, v)
 	} else {
-		s.Log(Panic, 0, m, v...)
+		s.write(Panic, 0, m, v)
+		s.flush()
 	}
 	panic(fmt.Sprintf(m, v...))
 }
 func (s *stream) Warning(m string, v ...interface{}) {
 	if s == nil {
-		Global.(LogWriter).Log(Warning, 0, m, v...)
+		logGlobal(Warning, m, v)
 		return
 	}
-	s.Log(Warning, 0, m, v...)
+	s.write(Warning, 0, m, v)
 }
 func (s *stream) Log(l Level, c int, m string, v ...interface{}) {
+	s.write(l, c, m, v)
+}
+func (s *stream) write(l Level, c int, m string, v []interface{}) {
 	if l == Print {
-		// NOTE(dij): Duplicate code here to prevent loops.
-		if s.l > s.p {
-			return
-		}
-		if len(m) == 0 {
-			s.Output(3+c, "["+s.p.String()+"]: "+fmt.Sprint(v...))
-		} else {
-			s.Output(3+c, "["+s.p.String()+"]: "+fmt.Sprintf(m, v...))
-		}
-		return
+		l = s.p.Level()
 	}
-	if s.l > l {
+	if s.l.Level() > l {
 		return
 	}
-	if len(m) == 0 {
-		s.Output(3+c, "["+l.String()+"]: "+fmt.Sprint(v...))
-	} else {
-		s.Output(3+c, "["+l.String()+"]: "+fmt.Sprintf(m, v...))
-	}
+	s.log(3+c, l, s.x, message(m, v))
 }
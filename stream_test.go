@@ -0,0 +1,53 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func tempDir(t *testing.T) string {
+	// NOTE(dij): 't.TempDir' requires Go 1.15.
+	d, err := ioutil.TempDir("", "logx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(d) })
+	return d
+}
+
+func TestPrintLevelOption(t *testing.T) {
+	var b bytes.Buffer
+	l := Writer(&b, Trace, Flags(0), PrintLevel(Error))
+	l.Print("writer")
+	if s := b.String(); s != "[ERROR]: writer\n" {
+		t.Fatalf("unexpected Writer output %q", s)
+	}
+	p := filepath.Join(tempDir(t), "print.log")
+	f, err := File(p, Trace, Flags(0), PrintLevel(Debug))
+	if err != nil {
+		t.Fatalf("File returned an error: %s", err)
+	}
+	f.Printf("file %d", 1)
+	f.(*file).Close()
+	if d, _ := ioutil.ReadFile(p); string(d) != "[DEBUG]: file 1\n" {
+		t.Fatalf("unexpected File output %q", d)
+	}
+}
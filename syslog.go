@@ -0,0 +1,224 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// RFC3164 is a logging setting that instructs a syslog backed Log to use the
+// older BSD syslog format (RFC 3164) instead of the default RFC 5424 format.
+//
+// This setting has no effect on non-syslog backed logging instances.
+const RFC3164 = settingRFC(true)
+
+// Syslog facility values that can be used as an Option for syslog backed Logs.
+// The default Facility is 'FacilityUser'.
+const (
+	FacilityKernel Facility = iota
+	FacilityUser
+	FacilityMail
+	FacilityDaemon
+	FacilityAuth
+	FacilitySyslog
+	FacilityLPR
+	FacilityNews
+	FacilityUUCP
+	FacilityCron
+	FacilityAuthPriv
+	FacilityFTP
+	FacilityNTP
+	FacilityAudit
+	FacilityAlert
+	FacilityClock
+	FacilityLocal0
+	FacilityLocal1
+	FacilityLocal2
+	FacilityLocal3
+	FacilityLocal4
+	FacilityLocal5
+	FacilityLocal6
+	FacilityLocal7
+)
+
+const (
+	timeRFC3164 = "Jan _2 15:04:05"
+	timeRFC5424 = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+// Facility is an alias of a byte that represents a syslog facility. Facility
+// values can be used as an Option for syslog backed Logs.
+type Facility uint8
+type settingRFC bool
+type syslog struct {
+	h string
+	a string
+	p int
+	f Facility
+	o bool
+}
+
+// Syslog will attempt to create a syslog backed Log instance that will write to
+// the syslog server at the specified network and address.
+//
+// The network may be "udp", "tcp", "tls", "unix" or "unixgram" (or any other
+// network supported by 'net.Dial'). If the network and address are empty, the
+// local syslog socket will be used with the RFC 3164 format. Entries sent over
+// "tcp" or "tls" are framed using octet-counting (RFC 6587).
+//
+// The first connection is made when this function is called. If the connection
+// is dropped, entries are held in an in-memory backlog while a background goroutine
+// attempts to reconnect, the same as a Log created with 'Network'. The 'Backoff',
+// 'WriteTimeout' and 'Backlog' options can be used to change this behavior.
+//
+// The prefix of the Log is used as the syslog APP-NAME (or TAG), which defaults
+// to the program name. The Facility, 'RFC3164' and 'TLS' options can be used to
+// change the syslog output. Each Level is mapped to the closest syslog severity.
+// The 'FlagFileLong' and 'FlagFileShort' flags will add the caller to each message,
+// but the other flags are ignored.
+//
+// The returned Log supports the 'Close', 'Sync' and 'Dropped' functions.
+func Syslog(n, a string, o ...Option) (Log, error) {
+	var (
+		r settingRFC
+		f = FacilityUser
+		p bool
+	)
+	for i := range o {
+		if o[i] == nil {
+			continue
+		}
+		switch o[i].setting() {
+		case setFacility:
+			f, _ = o[i].(Facility)
+		case setRFC:
+			r, _ = o[i].(settingRFC)
+		case setPrefix:
+			p = true
+		}
+	}
+	c := newConn(n, a, o)
+	if len(n) == 0 && len(a) == 0 {
+		if err := c.local(); err != nil {
+			return nil, errors.New("cannot connect to local syslog: " + err.Error())
+		}
+		r = true
+	} else {
+		var err error
+		if c.c, err = dial(n, a, c.t); err != nil {
+			return nil, errors.New(`cannot connect to syslog "` + a + `": ` + err.Error())
+		}
+	}
+	x := &syslog{f: f, o: bool(r), p: os.Getpid()}
+	if c.n != "unix" && c.n != "unixgram" {
+		if x.h, _ = os.Hostname(); len(x.h) == 0 {
+			x.h = "-"
+		}
+	}
+	switch c.n {
+	case "tcp", "tcp4", "tcp6", "tls":
+		c.f = true
+	}
+	if !p {
+		o = append(o, Prefix(filepath.Base(os.Args[0])))
+	}
+	return &network{c: c, stream: *Writer(c, append(o, Format(x))...).(*stream)}, nil
+}
+func (c *netConn) local() error {
+	for _, n := range [...]string{"unixgram", "unix"} {
+		for _, a := range [...]string{"/dev/log", "/var/run/syslog", "/var/run/log"} {
+			if x, err := dial(n, a, nil); err == nil {
+				c.n, c.a, c.c = n, a, x
+				return nil
+			}
+		}
+	}
+	return errors.New("no local syslog socket found")
+}
+func (Facility) setting() setting {
+	return setFacility
+}
+func (settingRFC) setting() setting {
+	return setRFC
+}
+func severity(l Level) int {
+	switch l {
+	case Trace, Debug:
+		return 7
+	case Info:
+		return 6
+	case Warning:
+		return 4
+	case Error:
+		return 3
+	case Fatal:
+		return 2
+	case Panic:
+		return 1
+	}
+	return 5
+}
+func appendPrintable(b []byte, s string, n int) []byte {
+	if len(s) == 0 {
+		return append(b, '-')
+	}
+	if len(s) > n {
+		s = s[:n]
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] <= ' ' || s[i] >= 0x7F {
+			b = append(b, '_')
+		} else {
+			b = append(b, s[i])
+		}
+	}
+	return b
+}
+func (s *syslog) Format(b []byte, f uint8, r Record) []byte {
+	b = append(b, '<')
+	b = strconv.AppendInt(b, int64(s.f)*8+int64(severity(r.Level)), 10)
+	b = append(b, '>')
+	if s.o {
+		b = r.Time.AppendFormat(b, timeRFC3164)
+		if b = append(b, ' '); len(s.h) > 0 && s.h != "-" {
+			b = append(appendPrintable(b, s.h, 255), ' ')
+		}
+		b = append(appendPrintable(b, r.Prefix, 32), '[')
+		b = append(strconv.AppendInt(b, int64(s.p), 10), ']', ':', ' ')
+	} else {
+		b = append(b, '1', ' ')
+		b = append(r.Time.AppendFormat(b, timeRFC5424), ' ')
+		if len(s.h) > 0 {
+			b = append(appendPrintable(b, s.h, 255), ' ')
+		} else {
+			b = append(b, '-', ' ')
+		}
+		b = append(appendPrintable(b, r.Prefix, 48), ' ')
+		b = append(strconv.AppendInt(b, int64(s.p), 10), " - - "...)
+	}
+	if f&(FlagFileLong|FlagFileShort) != 0 {
+		b = append(append(b, r.File...), ':')
+		b = append(strconv.AppendInt(b, int64(r.Line), 10), ':', ' ')
+	}
+	m := r.Message
+	if len(m) > 0 && m[len(m)-1] == '\n' {
+		m = m[:len(m)-1]
+	}
+	return append(appendFields(append(b, m...), r.Fields), '\n')
+}
@@ -0,0 +1,88 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyslogReconnect(t *testing.T) {
+	n, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer n.Close()
+	l, err := Syslog("tcp", n.Addr().String(), Backoff(time.Millisecond*10, time.Millisecond*50))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.(io.Closer).Close()
+	c, err := n.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Warning("first")
+	if m := readFrame(t, c); !strings.HasSuffix(m, "first") {
+		t.Fatalf("unexpected message %q", m)
+	}
+	c.Close()
+	r := make(chan net.Conn, 1)
+	go func() {
+		if x, err := n.Accept(); err == nil {
+			r <- x
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		s := time.Now()
+		l.Warning("second")
+		if d := time.Since(s); d > time.Second {
+			t.Fatalf("write blocked for %s while disconnected", d)
+		}
+		select {
+		case c = <-r:
+			defer c.Close()
+			if m := readFrame(t, c); !strings.HasSuffix(m, "second") {
+				t.Fatalf("unexpected message %q", m)
+			}
+			return
+		case <-time.After(time.Millisecond * 10):
+		}
+	}
+	t.Fatal("Log did not reconnect")
+}
+func readFrame(t *testing.T, c net.Conn) string {
+	c.SetReadDeadline(time.Now().Add(time.Second * 5))
+	r := bufio.NewReader(c)
+	s, err := r.ReadString(' ')
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := make([]byte, n)
+	if _, err = io.ReadFull(r, b); err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
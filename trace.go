@@ -0,0 +1,133 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"context"
+	"sync"
+)
+
+const (
+	// FieldTraceID is the Field name used for the trace ID returned by the
+	// TraceExtractor when using any of the '*Context' logging functions.
+	FieldTraceID = "trace_id"
+	// FieldSpanID is the Field name used for the span ID returned by the
+	// TraceExtractor when using any of the '*Context' logging functions.
+	FieldSpanID = "span_id"
+)
+
+var tracer struct {
+	f TraceExtractor
+	sync.RWMutex
+}
+
+type ctxTrace struct{}
+type traceIDs struct {
+	t, s string
+}
+
+// TraceExtractor is a function that returns the trace and span IDs of the active
+// trace stored in a Context. Empty strings should be returned for any IDs that
+// are not present.
+//
+// This can be used to link logging entries to any tracing library without a
+// direct dependency on it.
+type TraceExtractor func(context.Context) (string, string)
+
+// SetTraceExtractor sets the TraceExtractor used by all the '*Context' logging
+// functions. The returned IDs are added to the entry as the "trace_id" and
+// "span_id" Fields.
+//
+// If the supplied TraceExtractor is nil, the default TraceExtractor will be
+// used, which returns the IDs stored by 'WithTraceparent'.
+func SetTraceExtractor(f TraceExtractor) {
+	tracer.Lock()
+	tracer.f = f
+	tracer.Unlock()
+}
+func isHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if (s[i] < '0' || s[i] > '9') && (s[i] < 'a' || s[i] > 'f') {
+			return false
+		}
+	}
+	return true
+}
+func isZero(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '0' {
+			return false
+		}
+	}
+	return true
+}
+func traceFields(x context.Context, f []Field) []Field {
+	tracer.RLock()
+	e := tracer.f
+	tracer.RUnlock()
+	if e == nil {
+		e = traceparent
+	}
+	t, s := e(x)
+	if len(t) > 0 {
+		f = append(f, Field{Key: FieldTraceID, Value: t})
+	}
+	if len(s) > 0 {
+		f = append(f, Field{Key: FieldSpanID, Value: s})
+	}
+	return f
+}
+func traceparent(x context.Context) (string, string) {
+	if v, ok := x.Value(ctxTrace{}).(traceIDs); ok {
+		return v.t, v.s
+	}
+	return "", ""
+}
+
+// ParseTraceparent parses the supplied W3C Trace Context 'traceparent' header
+// value and returns the trace and span (parent) IDs contained in it.
+//
+// The boolean will be false if the value is not valid.
+func ParseTraceparent(s string) (string, string, bool) {
+	// NOTE(dij): Format is "VV-TTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTT-SSSSSSSSSSSSSSSS-FF"
+	//            and future versions may append "-" and more data.
+	if len(s) < 55 || s[2] != '-' || s[35] != '-' || s[52] != '-' {
+		return "", "", false
+	}
+	if v := s[0:2]; !isHex(v) || v == "ff" || (v == "00" && len(s) != 55) || (len(s) > 55 && s[55] != '-') {
+		return "", "", false
+	}
+	if !isHex(s[3:35]) || isZero(s[3:35]) || !isHex(s[36:52]) || isZero(s[36:52]) || !isHex(s[53:55]) {
+		return "", "", false
+	}
+	return s[3:35], s[36:52], true
+}
+
+// WithTraceparent returns a copy of the parent Context that stores the trace and
+// span IDs contained in the supplied W3C Trace Context 'traceparent' header value.
+// These IDs are used by the default TraceExtractor.
+//
+// If the value is not valid, the parent Context is returned unchanged.
+func WithTraceparent(x context.Context, s string) context.Context {
+	if x == nil {
+		x = context.Background()
+	}
+	t, p, ok := ParseTraceparent(s)
+	if !ok {
+		return x
+	}
+	return context.WithValue(x, ctxTrace{}, traceIDs{t: t, s: p})
+}
@@ -0,0 +1,71 @@
+// Copyright 2021 - 2023 PurpleSec Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logx
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	const (
+		x = "4bf92f3577b34da6a3ce929d0e0e4736"
+		y = "00f067aa0ba902b7"
+	)
+	for _, v := range []struct {
+		n, s string
+		ok   bool
+	}{
+		{"valid", "00-" + x + "-" + y + "-01", true},
+		{"valid unsampled", "00-" + x + "-" + y + "-00", true},
+		{"version ff", "ff-" + x + "-" + y + "-01", false},
+		{"zero trace", "00-00000000000000000000000000000000-" + y + "-01", false},
+		{"zero span", "00-" + x + "-0000000000000000-01", false},
+		{"uppercase trace", "00-4BF92F3577B34DA6A3CE929D0E0E4736-" + y + "-01", false},
+		{"uppercase version", "0A-" + x + "-" + y + "-01", false},
+		{"uppercase flags", "00-" + x + "-" + y + "-0A", false},
+		{"version 00 with data", "00-" + x + "-" + y + "-01-extra", false},
+		{"future version with data", "cc-" + x + "-" + y + "-01-extra", true},
+		{"future version without dash", "cc-" + x + "-" + y + "-01extra", false},
+		{"future version", "01-" + x + "-" + y + "-01", true},
+		{"short", "00-" + x + "-" + y + "-1", false},
+		{"bad separator", "00_" + x + "-" + y + "-01", false},
+		{"non-hex span", "00-" + x + "-00f067aa0ba902bz-01", false},
+		{"empty", "", false},
+	} {
+		t.Run(v.n, func(t *testing.T) {
+			a, b, ok := ParseTraceparent(v.s)
+			if ok != v.ok {
+				t.Fatalf("ParseTraceparent(%q) returned %t, expected %t", v.s, ok, v.ok)
+			}
+			if ok && (a != x || b != y) {
+				t.Fatalf("ParseTraceparent(%q) returned %q, %q", v.s, a, b)
+			}
+			if !ok && (len(a) > 0 || len(b) > 0) {
+				t.Fatalf("ParseTraceparent(%q) returned IDs for an invalid value", v.s)
+			}
+		})
+	}
+}
+func TestWithTraceparent(t *testing.T) {
+	x := WithTraceparent(context.Background(), "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if a, b := traceparent(x); a != "4bf92f3577b34da6a3ce929d0e0e4736" || b != "00f067aa0ba902b7" {
+		t.Fatalf("traceparent returned %q, %q", a, b)
+	}
+	if a, b := traceparent(WithTraceparent(context.Background(), "invalid")); len(a) > 0 || len(b) > 0 {
+		t.Fatal("traceparent returned IDs for an invalid value")
+	}
+}